@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -17,10 +18,15 @@ import (
 	"cs-projects-ink-eth-monitor/internal/logger"
 	"cs-projects-ink-eth-monitor/internal/metrics"
 	"cs-projects-ink-eth-monitor/internal/monitor"
+	"cs-projects-ink-eth-monitor/internal/server"
 )
 
+// shutdownTimeout 关闭HTTP服务时等待处理中请求的最长时间
+const shutdownTimeout = 5 * time.Second
+
 var (
 	configPath = flag.String("config", "conf/config.yaml", "配置文件路径")
+	rearmRule  = flag.String("rearm", "", "重新布防指定的应急规则（all 表示全部）后退出，需在服务停止时执行")
 )
 
 func main() {
@@ -33,6 +39,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 重新布防应急规则
+	if *rearmRule != "" {
+		if err := rearm(&cfg.Emergency, *rearmRule); err != nil {
+			fmt.Fprintf(os.Stderr, "重新布防失败: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 初始化日志
 	if err := logger.Init(&cfg.Log); err != nil {
 		fmt.Fprintf(os.Stderr, "初始化日志失败: %v\n", err)
@@ -57,16 +72,65 @@ func main() {
 	// 创建指标管理器
 	metricsManager := metrics.NewMetrics(&cfg.Prometheus, log)
 	defer metricsManager.Close()
+	clientManager.SetRPCObserver(metricsManager)
 
 	// 创建应急响应管理器
-	emergencyManager, err := emergency.NewManager(&cfg.Emergency, cfg.InkRPC, log)
+	emergencyManager, err := emergency.NewManager(ctx, &cfg.Emergency, cfg.InkRPC, metricsManager, log)
 	if err != nil {
 		log.Fatal("创建应急响应管理器失败", zap.Error(err))
 	}
 	defer emergencyManager.Close()
 
 	// 创建监控器
-	m := monitor.NewMonitor(cfg, clientManager, metricsManager, emergencyManager, log)
+	m, err := monitor.NewMonitor(cfg, clientManager, metricsManager, emergencyManager, log)
+	if err != nil {
+		log.Fatal("创建监控器失败", zap.Error(err))
+	}
+
+	// 启动HTTP服务，提供健康检查、运行状态和 /metrics 抓取
+	if cfg.Server.Enabled || cfg.Prometheus.ScrapeEnabled() {
+		srv := server.New(&cfg.Server, log)
+		srv.HandleHealth(m)
+		if cfg.Prometheus.ScrapeEnabled() {
+			srv.Handle("/metrics", metricsManager.Handler())
+		}
+		if err := srv.Start(); err != nil {
+			log.Fatal("启动HTTP服务失败", zap.Error(err))
+		}
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer shutdownCancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				log.Error("关闭HTTP服务失败", zap.Error(err))
+			}
+		}()
+	}
+
+	// 启动应急控制管理接口，单独监听（默认只监听本机），与公开的指标抓取端口分开
+	if cfg.Admin.Enabled {
+		token, err := server.LoadAdminToken(&cfg.Admin)
+		if err != nil {
+			log.Fatal("加载管理接口 Token 失败", zap.Error(err))
+		}
+		audit, err := server.NewAuditLog(cfg.Admin.GetAuditLog(), log)
+		if err != nil {
+			log.Fatal("打开审计日志失败", zap.Error(err))
+		}
+		defer audit.Close()
+
+		admin := server.New(&config.ServerConfig{ListenAddr: cfg.Admin.GetListenAddr()}, log)
+		admin.HandleAdmin(emergencyManager, token, audit)
+		if err := admin.Start(); err != nil {
+			log.Fatal("启动管理接口失败", zap.Error(err))
+		}
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer shutdownCancel()
+			if err := admin.Shutdown(shutdownCtx); err != nil {
+				log.Error("关闭管理接口失败", zap.Error(err))
+			}
+		}()
+	}
 
 	// 监听系统信号
 	sigChan := make(chan os.Signal, 1)
@@ -101,3 +165,24 @@ func main() {
 
 	log.Info("监控服务已成功推出")
 }
+
+// rearm 修改状态文件，重新布防应急规则
+func rearm(cfg *config.EmergencyConfig, rule string) error {
+	stateFile := cfg.StateFile
+	if stateFile == "" {
+		stateFile = emergency.DefaultStateFile
+	}
+	state, err := emergency.LoadStateStore(stateFile)
+	if err != nil {
+		return err
+	}
+	if rule == "all" {
+		rule = ""
+	}
+	count, err := state.Rearm(rule)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已重新布防 %d 个应急动作 (状态文件: %s)\n", count, stateFile)
+	return nil
+}
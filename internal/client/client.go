@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"go.uber.org/zap"
 
@@ -71,24 +73,167 @@ func (c *EthClient) Close() {
 }
 
 // ContractCaller 合约调用器 - 简化版实现
+// 调用按优先级在多个RPC节点间自动切换
 type ContractCaller struct {
-	client *ethclient.Client
-	logger *zap.Logger
+	pool      *endpointPool
+	block     *big.Int     // 固定读取的区块高度，nil 表示最新区块
+	blockHash *common.Hash // 固定读取的区块哈希，优先于 block
+	multicall *multicall   // Multicall3 批量读取配置
+	cache     *callCache   // 批量读取的结果缓存，nil 表示不使用缓存
+	logger    *zap.Logger
 }
 
 // NewContractCaller 创建合约调用器
 func NewContractCaller(rpcURL string, logger *zap.Logger) (*ContractCaller, error) {
-	client, err := ethclient.Dial(rpcURL)
+	return NewContractCallerWithEndpoints([]string{rpcURL}, HealthConfig{}, logger)
+}
+
+// NewContractCallerWithEndpoints 使用按优先级排列的多个RPC节点创建合约调用器
+func NewContractCallerWithEndpoints(rpcURLs []string, health HealthConfig, logger *zap.Logger) (*ContractCaller, error) {
+	pool, err := newEndpointPool(rpcURLs, health, logger)
 	if err != nil {
-		return nil, fmt.Errorf("连接RPC节点失败: %w", err)
+		return nil, err
 	}
 
 	return &ContractCaller{
-		client: client,
-		logger: logger,
+		pool:      pool,
+		multicall: newMulticall(MulticallConfig{}),
+		logger:    logger,
 	}, nil
 }
 
+// SetMulticall 设置 Multicall3 批量读取参数，应在派生调用器之前调用
+func (c *ContractCaller) SetMulticall(cfg MulticallConfig) {
+	c.multicall = newMulticall(cfg)
+}
+
+// SetObserver 设置RPC请求的指标接收方，chain 为指标的链标签，应在派生调用器之前调用
+func (c *ContractCaller) SetObserver(chain string, observer RPCObserver) {
+	c.pool.chain, c.pool.observer = chain, observer
+}
+
+// clone 返回共享节点连接和配置的调用器副本
+func (c *ContractCaller) clone() *ContractCaller {
+	cp := *c
+	return &cp
+}
+
+// callContract 执行 eth_call，节点故障时切换到下一个节点
+// 使用批量读取缓存时优先返回缓存结果
+func (c *ContractCaller) callContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	if c.cache != nil {
+		if result, ok, queued := c.cache.lookup(msg); ok {
+			return result, nil
+		} else if queued {
+			return nil, errPrefetch
+		}
+	}
+
+	var result []byte
+	err := c.pool.do(ctx, "eth_call", func(client *ethclient.Client) error {
+		var err error
+		if c.blockHash != nil {
+			result, err = client.CallContractAtHash(ctx, msg, *c.blockHash)
+		} else {
+			result, err = client.CallContract(ctx, msg, c.block)
+		}
+		return err
+	})
+	return result, err
+}
+
+// AtBlock 返回固定在指定区块高度读取的调用器，与原调用器共享节点连接
+// 同一调用器的所有读取来自同一区块，用于一轮轮询内相关读取的一致性
+func (c *ContractCaller) AtBlock(block *big.Int) *ContractCaller {
+	cp := c.clone()
+	cp.block, cp.blockHash, cp.cache = block, nil, nil
+	return cp
+}
+
+// AtBlockHash 返回固定在指定区块哈希读取的调用器，区块被重组后读取会失败而不是读到其他分叉的状态
+func (c *ContractCaller) AtBlockHash(hash common.Hash) *ContractCaller {
+	cp := c.clone()
+	cp.block, cp.blockHash, cp.cache = nil, &hash, nil
+	return cp
+}
+
+// Pinned 返回固定在首选节点最新区块读取的调用器及该区块高度
+func (c *ContractCaller) Pinned(ctx context.Context) (*ContractCaller, uint64, error) {
+	head, err := c.BlockNumber(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return c.AtBlock(new(big.Int).SetUint64(head)), head, nil
+}
+
+// Block 返回调用器固定读取的区块高度，未固定或按哈希固定时返回 nil
+func (c *ContractCaller) Block() *big.Int {
+	return c.block
+}
+
+// Providers 返回最多 n 个单节点调用器（n <= 0 表示全部），健康节点优先
+// 单节点调用器不会切换节点，也不使用批量读取缓存，用于多节点交叉验证；与原调用器共享连接，不需要单独关闭
+func (c *ContractCaller) Providers(n int) []*ContractCaller {
+	var providers []*ContractCaller
+	for _, e := range c.pool.candidates() {
+		if n > 0 && len(providers) >= n {
+			break
+		}
+		provider := c.clone()
+		provider.pool = &endpointPool{endpoints: []*endpoint{e}, health: c.pool.health, logger: c.logger, chain: c.pool.chain, observer: c.pool.observer}
+		provider.cache = nil
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// Endpoint 返回调用器首选节点的脱敏名称
+func (c *ContractCaller) Endpoint() string {
+	candidates := c.pool.candidates()
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0].name
+}
+
+// BlockNumber 获取最新区块高度
+func (c *ContractCaller) BlockNumber(ctx context.Context) (uint64, error) {
+	var head uint64
+	err := c.pool.do(ctx, "eth_blockNumber", func(client *ethclient.Client) error {
+		var err error
+		head, err = client.BlockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("获取区块高度失败: %w", err)
+	}
+	return head, nil
+}
+
+// HeaderByNumber 获取区块头，number 为 nil 时获取最新区块
+func (c *ContractCaller) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var header *types.Header
+	err := c.pool.do(ctx, "eth_getBlockByNumber", func(client *ethclient.Client) error {
+		var err error
+		header, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取区块头失败: %w", err)
+	}
+	return header, nil
+}
+
+// CheckHealth 检查所有节点的健康状态
+func (c *ContractCaller) CheckHealth(ctx context.Context) {
+	c.pool.checkHealth(ctx)
+}
+
+// Endpoints 返回所有节点的健康状态，按配置顺序排列
+func (c *ContractCaller) Endpoints() []EndpointHealth {
+	return c.pool.snapshot()
+}
+
 // CallBool 调用返回bool的方法
 func (c *ContractCaller) CallBool(ctx context.Context, contractAddr string, data []byte) (bool, error) {
 	msg := ethereum.CallMsg{
@@ -97,7 +242,7 @@ func (c *ContractCaller) CallBool(ctx context.Context, contractAddr string, data
 	}
 	copy(msg.To[:], common.HexToAddress(contractAddr).Bytes())
 
-	result, err := c.client.CallContract(ctx, msg, nil)
+	result, err := c.callContract(ctx, msg)
 	if err != nil {
 		return false, fmt.Errorf("调用合约失败: %w", err)
 	}
@@ -118,7 +263,7 @@ func (c *ContractCaller) CallUint256(ctx context.Context, contractAddr string, d
 	}
 	copy(msg.To[:], common.HexToAddress(contractAddr).Bytes())
 
-	result, err := c.client.CallContract(ctx, msg, nil)
+	result, err := c.callContract(ctx, msg)
 	if err != nil {
 		return nil, fmt.Errorf("调用合约失败: %w", err)
 	}
@@ -134,8 +279,20 @@ func (c *ContractCaller) CallUint256(ctx context.Context, contractAddr string, d
 
 // CallInt256 调用返回int256的方法（用于价格）
 func (c *ContractCaller) CallInt256(ctx context.Context, contractAddr string, data []byte) (*big.Int, error) {
-	// int256和uint256的解析方式相同，只是解释不同
-	return c.CallUint256(ctx, contractAddr, data)
+	// 按二进制补码解释返回值，负数价格不会被读成极大的正数
+	value, err := c.CallUint256(ctx, contractAddr, data)
+	if err != nil {
+		return nil, err
+	}
+	return ToInt256(value), nil
+}
+
+// ToInt256 将按 uint256 解析的值按二进制补码解释为 int256
+func ToInt256(value *big.Int) *big.Int {
+	if value.BitLen() < 256 {
+		return value
+	}
+	return new(big.Int).Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
 }
 
 // CallRaw 调用合约方法并返回原始字节数据
@@ -146,7 +303,7 @@ func (c *ContractCaller) CallRaw(ctx context.Context, contractAddr string, data
 	}
 	copy(msg.To[:], common.HexToAddress(contractAddr).Bytes())
 
-	result, err := c.client.CallContract(ctx, msg, nil)
+	result, err := c.callContract(ctx, msg)
 	if err != nil {
 		return nil, fmt.Errorf("调用合约失败: %w", err)
 	}
@@ -156,8 +313,8 @@ func (c *ContractCaller) CallRaw(ctx context.Context, contractAddr string, data
 
 // Close 关闭客户端
 func (c *ContractCaller) Close() {
-	if c.client != nil {
-		c.client.Close()
+	if c.pool != nil {
+		c.pool.close()
 		c.logger.Info("关闭RPC客户端")
 	}
 }
@@ -172,19 +329,21 @@ type ClientManager struct {
 // NewClientManager 创建客户端管理器
 func NewClientManager(cfg *config.Config, logger *zap.Logger) (*ClientManager, error) {
 	// 创建Ethereum客户端
-	ethClient, err := NewContractCaller(cfg.EthRPC, logger)
+	ethClient, err := NewContractCallerWithEndpoints(cfg.GetEthereumRPCs(), chainHealth(&cfg.Ethereum), logger.With(zap.String("chain", "ethereum")))
 	if err != nil {
 		return nil, fmt.Errorf("创建Ethereum客户端失败: %w", err)
 	}
-	logger.Info("成功创建Ethereum客户端", zap.String("rpc_url", cfg.EthRPC))
+	ethClient.SetMulticall(chainMulticall(&cfg.Ethereum))
+	logger.Info("成功创建Ethereum客户端", zap.Strings("endpoints", endpointNames(ethClient)))
 
 	// 创建INK客户端
-	inkClient, err := NewContractCaller(cfg.InkRPC, logger)
+	inkClient, err := NewContractCallerWithEndpoints(cfg.GetInkRPCs(), chainHealth(&cfg.Ink), logger.With(zap.String("chain", "ink")))
 	if err != nil {
 		ethClient.Close()
 		return nil, fmt.Errorf("创建INK客户端失败: %w", err)
 	}
-	logger.Info("成功创建INK客户端", zap.String("rpc_url", cfg.InkRPC))
+	inkClient.SetMulticall(chainMulticall(&cfg.Ink))
+	logger.Info("成功创建INK客户端", zap.Strings("endpoints", endpointNames(inkClient)))
 
 	return &ClientManager{
 		ethereumClient: ethClient,
@@ -193,6 +352,60 @@ func NewClientManager(cfg *config.Config, logger *zap.Logger) (*ClientManager, e
 	}, nil
 }
 
+// chainHealth 根据链配置生成节点健康判定参数
+func chainHealth(cfg *config.ChainConfig) HealthConfig {
+	return HealthConfig{
+		MaxBlockLag:  cfg.MaxBlockLag,
+		MaxErrorRate: cfg.MaxErrorRate,
+		MaxLatency:   cfg.GetMaxLatency(),
+	}
+}
+
+// chainMulticall 根据链配置生成批量读取参数
+func chainMulticall(cfg *config.ChainConfig) MulticallConfig {
+	mc := MulticallConfig{BatchSize: cfg.MulticallBatchSize, Disabled: cfg.DisableMulticall}
+	if cfg.MulticallAddress != "" {
+		mc.Address = common.HexToAddress(cfg.MulticallAddress)
+	}
+	return mc
+}
+
+// endpointNames 返回脱敏后的节点名称
+func endpointNames(c *ContractCaller) []string {
+	var names []string
+	for _, h := range c.Endpoints() {
+		names = append(names, h.Endpoint)
+	}
+	return names
+}
+
+// CheckHealth 检查两条链所有节点的健康状态
+func (m *ClientManager) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range []*ContractCaller{m.ethereumClient, m.inkClient} {
+		wg.Add(1)
+		go func(c *ContractCaller) {
+			defer wg.Done()
+			c.CheckHealth(ctx)
+		}(c)
+	}
+	wg.Wait()
+}
+
+// EndpointHealth 返回按链分组的节点健康状态
+func (m *ClientManager) EndpointHealth() map[string][]EndpointHealth {
+	return map[string][]EndpointHealth{
+		"ethereum": m.ethereumClient.Endpoints(),
+		"ink":      m.inkClient.Endpoints(),
+	}
+}
+
+// SetRPCObserver 设置两条链RPC请求的指标接收方
+func (m *ClientManager) SetRPCObserver(observer RPCObserver) {
+	m.ethereumClient.SetObserver("ethereum", observer)
+	m.inkClient.SetObserver("ink", observer)
+}
+
 // GetEthereumClient 获取Ethereum客户端
 func (m *ClientManager) GetEthereumClient() *ContractCaller {
 	return m.ethereumClient
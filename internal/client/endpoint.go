@@ -0,0 +1,341 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// 节点健康检查默认参数
+const (
+	DefaultMaxBlockLag  = 5
+	DefaultMaxErrorRate = 0.5
+	ewmaWeight          = 0.2 // 延迟和错误率的指数移动平均权重
+)
+
+// HealthConfig 节点健康判定参数
+type HealthConfig struct {
+	MaxBlockLag  uint64        // 区块高度落后最高节点的最大块数
+	MaxErrorRate float64       // 最大错误率（指数移动平均）
+	MaxLatency   time.Duration // 最大请求延迟（指数移动平均），0 表示不限制
+}
+
+// withDefaults 补全未设置的参数
+func (h HealthConfig) withDefaults() HealthConfig {
+	if h.MaxBlockLag == 0 {
+		h.MaxBlockLag = DefaultMaxBlockLag
+	}
+	if h.MaxErrorRate <= 0 {
+		h.MaxErrorRate = DefaultMaxErrorRate
+	}
+	return h
+}
+
+// EndpointHealth 节点健康状态快照
+type EndpointHealth struct {
+	Endpoint  string        `json:"endpoint"` // 脱敏后的节点地址（仅保留主机名）
+	Healthy   bool          `json:"healthy"`
+	Head      uint64        `json:"head"`
+	Lag       uint64        `json:"lag"`
+	Latency   time.Duration `json:"latency"`
+	ErrorRate float64       `json:"error_rate"`
+	LastError string        `json:"last_error,omitempty"`
+}
+
+// endpoint 单个RPC节点
+type endpoint struct {
+	url     string
+	name    string
+	client  *ethclient.Client
+	health  EndpointHealth
+	checked bool // 是否完成过健康检查，之前的健康状态未知
+	mu      sync.Mutex
+}
+
+// newEndpoint 连接节点，首次健康检查前节点视为不健康（状态未知），连接失败时在健康检查时重新连接
+func newEndpoint(rawURL, name string) (*endpoint, error) {
+	e := &endpoint{url: rawURL, name: name}
+	e.health = EndpointHealth{Endpoint: name}
+	client, err := ethclient.Dial(rawURL)
+	if err != nil {
+		e.health.LastError = e.redact(err).Error()
+		return e, err
+	}
+	e.client = client
+	return e, nil
+}
+
+// getClient 返回节点客户端，未连接时返回 nil
+func (e *endpoint) getClient() *ethclient.Client {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.client
+}
+
+// redial 重新连接节点
+func (e *endpoint) redial(ctx context.Context) (*ethclient.Client, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.client != nil {
+		return e.client, nil
+	}
+	client, err := ethclient.DialContext(ctx, e.url)
+	if err != nil {
+		return nil, err
+	}
+	e.client = client
+	return client, nil
+}
+
+// record 记录一次请求的延迟和结果
+func (e *endpoint) record(latency time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	failed := 0.0
+	if err != nil {
+		failed = 1
+		e.health.LastError = e.redact(err).Error()
+	} else {
+		e.health.Latency = ewmaDuration(e.health.Latency, latency)
+	}
+	e.health.ErrorRate = e.health.ErrorRate*(1-ewmaWeight) + failed*ewmaWeight
+}
+
+// redact 去掉错误信息中的完整节点地址（HTTP 错误会带上包含 API key 的 URL）
+func (e *endpoint) redact(err error) error {
+	if !strings.Contains(err.Error(), e.url) {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), e.url, e.name))
+}
+
+// snapshot 返回健康状态副本
+func (e *endpoint) snapshot() EndpointHealth {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.health
+}
+
+// ewmaDuration 计算延迟的指数移动平均
+func ewmaDuration(prev, sample time.Duration) time.Duration {
+	if prev == 0 {
+		return sample
+	}
+	return time.Duration(float64(prev)*(1-ewmaWeight) + float64(sample)*ewmaWeight)
+}
+
+// redactURL 只保留节点主机名，避免在日志和指标中泄露 API key
+func redactURL(rawURL string, index int) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("endpoint-%d", index)
+	}
+	return u.Host
+}
+
+// isDeterministic 判断错误是否与节点无关（合约 revert），此类错误不切换节点
+func isDeterministic(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) && dataErr.ErrorData() != nil {
+		return true
+	}
+	return strings.Contains(err.Error(), "execution reverted")
+}
+
+// endpointPool 按优先级排列的RPC节点池
+type endpointPool struct {
+	endpoints []*endpoint
+	health    HealthConfig
+	logger    *zap.Logger
+	chain     string
+	observer  RPCObserver // 为 nil 时不导出请求指标
+}
+
+// newEndpointPool 连接所有节点，全部连接失败时返回错误
+func newEndpointPool(urls []string, health HealthConfig, logger *zap.Logger) (*endpointPool, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("未配置RPC节点")
+	}
+
+	p := &endpointPool{health: health.withDefaults(), logger: logger}
+	names := make(map[string]int)
+	connected := 0
+	for i, rawURL := range urls {
+		name := redactURL(rawURL, i)
+		if n := names[name]; n > 0 {
+			name = fmt.Sprintf("%s#%d", name, n)
+		}
+		names[redactURL(rawURL, i)]++
+
+		e, err := newEndpoint(rawURL, name)
+		if err != nil {
+			logger.Warn("连接RPC节点失败，将在健康检查时重试", zap.String("endpoint", name), zap.Error(e.redact(err)))
+		} else {
+			connected++
+		}
+		p.endpoints = append(p.endpoints, e)
+	}
+	if connected == 0 {
+		return nil, fmt.Errorf("连接RPC节点失败: 全部 %d 个节点均不可用", len(urls))
+	}
+	return p, nil
+}
+
+// candidates 返回本次调用的节点顺序：健康节点按配置顺序优先，不健康节点作为最后手段
+func (p *endpointPool) candidates() []*endpoint {
+	var healthy, unhealthy []*endpoint
+	for _, e := range p.endpoints {
+		if e.snapshot().Healthy {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// do 依次在节点上执行调用直到成功，合约 revert 等确定性错误直接返回
+// method 为 JSON-RPC 方法名，用于请求指标
+func (p *endpointPool) do(ctx context.Context, method string, fn func(*ethclient.Client) error) error {
+	var errs []error
+	for _, e := range p.candidates() {
+		client := e.getClient()
+		if client == nil {
+			// 连接失败且健康检查重连也未成功的节点
+			p.logger.Warn("RPC节点未连接，跳过", zap.String("endpoint", e.name), zap.String("last_error", e.snapshot().LastError))
+			errs = append(errs, fmt.Errorf("%s: 节点未连接", e.name))
+			continue
+		}
+
+		start := time.Now()
+		err := fn(client)
+		p.observe(e, method, time.Since(start), err)
+		if err == nil {
+			e.record(time.Since(start), nil)
+			return nil
+		}
+		if isDeterministic(err) {
+			e.record(time.Since(start), nil)
+			return err
+		}
+		e.record(time.Since(start), err)
+		err = e.redact(err)
+		errs = append(errs, fmt.Errorf("%s: %w", e.name, err))
+
+		if ctx.Err() != nil {
+			break
+		}
+		p.logger.Warn("RPC节点调用失败，切换到下一个节点", zap.String("endpoint", e.name), zap.Error(err))
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("没有可用的RPC节点")
+	}
+	return errors.Join(errs...)
+}
+
+// checkHealth 查询所有节点的区块高度，按高度落后、错误率和延迟判定节点健康状态
+func (p *endpointPool) checkHealth(ctx context.Context) {
+	heads := make([]uint64, len(p.endpoints))
+	errs := make([]error, len(p.endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			client, err := e.redial(ctx)
+			if err != nil {
+				errs[i] = err
+				e.record(0, err)
+				return
+			}
+			start := time.Now()
+			heads[i], errs[i] = client.BlockNumber(ctx)
+			p.observe(e, "eth_blockNumber", time.Since(start), errs[i])
+			e.record(time.Since(start), errs[i])
+		}(i, e)
+	}
+	wg.Wait()
+
+	var maxHead uint64
+	for i := range p.endpoints {
+		if errs[i] == nil && heads[i] > maxHead {
+			maxHead = heads[i]
+		}
+	}
+
+	for i, e := range p.endpoints {
+		e.mu.Lock()
+		prev := e.health.Healthy
+		first := !e.checked
+		e.checked = true
+		h := &e.health
+		if errs[i] == nil {
+			h.Head = heads[i]
+			h.Lag = maxHead - heads[i]
+		} else {
+			// 查询失败时落后块数未知，不保留上一次的值
+			h.Lag = 0
+		}
+		h.Healthy = errs[i] == nil &&
+			h.Lag <= p.health.MaxBlockLag &&
+			h.ErrorRate <= p.health.MaxErrorRate &&
+			(p.health.MaxLatency == 0 || h.Latency <= p.health.MaxLatency)
+		if errs[i] == nil && h.Healthy {
+			h.LastError = ""
+		}
+		snapshot := *h
+		e.mu.Unlock()
+
+		if (prev || first) && !snapshot.Healthy {
+			p.logger.Warn("RPC节点不健康",
+				zap.String("endpoint", snapshot.Endpoint),
+				zap.Uint64("lag", snapshot.Lag),
+				zap.Float64("error_rate", snapshot.ErrorRate),
+				zap.Duration("latency", snapshot.Latency),
+				zap.String("last_error", snapshot.LastError),
+			)
+		} else if !prev && !first && snapshot.Healthy {
+			p.logger.Info("RPC节点恢复健康", zap.String("endpoint", snapshot.Endpoint))
+		}
+	}
+}
+
+// observe 将一次请求的延迟和错误分类交给 observer
+func (p *endpointPool) observe(e *endpoint, method string, latency time.Duration, err error) {
+	if p.observer == nil {
+		return
+	}
+	class := ""
+	if err != nil {
+		class = ErrorClass(err)
+	}
+	p.observer.ObserveRPC(p.chain, e.name, method, latency, class)
+}
+
+// snapshot 返回所有节点的健康状态
+func (p *endpointPool) snapshot() []EndpointHealth {
+	health := make([]EndpointHealth, len(p.endpoints))
+	for i, e := range p.endpoints {
+		health[i] = e.snapshot()
+	}
+	return health
+}
+
+// close 关闭所有节点连接
+func (p *endpointPool) close() {
+	for _, e := range p.endpoints {
+		if client := e.getClient(); client != nil {
+			client.Close()
+		}
+	}
+}
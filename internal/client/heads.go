@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// DefaultHeadPollInterval 轮询 eth_blockNumber 的默认间隔
+const DefaultHeadPollInterval = time.Second
+
+// PollHeads 轮询 eth_blockNumber，区块高度增加时调用 handle，直到 ctx 取消
+func (c *ContractCaller) PollHeads(ctx context.Context, interval time.Duration, handle func(uint64)) {
+	if interval <= 0 {
+		interval = DefaultHeadPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last uint64
+	for {
+		head, err := c.BlockNumber(ctx)
+		if err != nil {
+			c.logger.Debug("轮询区块高度失败", zap.Error(err))
+		} else if head > last {
+			last = head
+			handle(head)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SubscribeHeads 通过 WebSocket 节点订阅新区块，断开后自动重连，直到 ctx 取消
+func SubscribeHeads(ctx context.Context, wsURL string, handle func(uint64), logger *zap.Logger) {
+	keepSubscribed(ctx, wsURL, "新区块", nil, logger, func(client *ethclient.Client, onSubscribed func()) error {
+		heads := make(chan *types.Header, 16)
+		sub, err := client.SubscribeNewHead(ctx, heads)
+		if err != nil {
+			return fmt.Errorf("订阅新区块失败: %w", err)
+		}
+		defer sub.Unsubscribe()
+		onSubscribed()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-sub.Err():
+				return subscriptionClosed(err)
+			case head := <-heads:
+				handle(head.Number.Uint64())
+			}
+		}
+	})
+}
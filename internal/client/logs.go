@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// 日志监听默认参数
+const (
+	DefaultLogPollInterval = 2 * time.Second
+	DefaultLogBlockRange   = 1000 // 单次 eth_getLogs 查询的最大区块数
+	resubscribeDelay       = 5 * time.Second
+)
+
+// FilterLogs 查询匹配的合约日志，节点故障时切换到下一个节点
+func (c *ContractCaller) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	err := c.pool.do(ctx, "eth_getLogs", func(client *ethclient.Client) error {
+		var err error
+		logs, err = client.FilterLogs(ctx, q)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询日志失败: %w", err)
+	}
+	return logs, nil
+}
+
+// PollLogs 按区块范围轮询 eth_getLogs，只处理启动之后的新区块，直到 ctx 取消
+// 每次查询不超过 maxRange 个区块，落后较多时分段追赶
+func (c *ContractCaller) PollLogs(ctx context.Context, q ethereum.FilterQuery, interval time.Duration, maxRange uint64, handle func(types.Log)) {
+	if interval <= 0 {
+		interval = DefaultLogPollInterval
+	}
+	if maxRange == 0 {
+		maxRange = DefaultLogBlockRange
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var next uint64 // 下一个待查询的区块，0 表示尚未确定起点
+	for {
+		head, err := c.BlockNumber(ctx)
+		if err != nil {
+			c.logger.Warn("轮询日志获取区块高度失败", zap.Error(err))
+		} else if next == 0 {
+			next = head + 1
+		}
+		for err == nil && next <= head {
+			to := min(head, next+maxRange-1)
+			q.FromBlock, q.ToBlock = new(big.Int).SetUint64(next), new(big.Int).SetUint64(to)
+			var logs []types.Log
+			if logs, err = c.FilterLogs(ctx, q); err != nil {
+				c.logger.Warn("轮询日志失败，下次重试", zap.Uint64("from", next), zap.Uint64("to", to), zap.Error(err))
+				break
+			}
+			for _, log := range logs {
+				handle(log)
+			}
+			next = to + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SubscribeLogs 通过 WebSocket 节点订阅匹配的合约日志，断开后自动重连，直到 ctx 取消
+// 重连成功后调用 onReconnect，调用方可以借此补偿断开期间可能错过的事件
+func SubscribeLogs(ctx context.Context, wsURL string, q ethereum.FilterQuery, handle func(types.Log), onReconnect func(), logger *zap.Logger) {
+	keepSubscribed(ctx, wsURL, "合约日志", onReconnect, logger, func(client *ethclient.Client, onSubscribed func()) error {
+		logs := make(chan types.Log, 64)
+		sub, err := client.SubscribeFilterLogs(ctx, q, logs)
+		if err != nil {
+			return fmt.Errorf("订阅日志失败: %w", err)
+		}
+		defer sub.Unsubscribe()
+		onSubscribed()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-sub.Err():
+				return subscriptionClosed(err)
+			case log := <-logs:
+				handle(log)
+			}
+		}
+	})
+}
+
+// keepSubscribed 连接 WebSocket 节点并执行订阅，订阅中断后等待一段时间重连，直到 ctx 取消
+// 第二次及之后订阅成功时调用 onReconnect
+func keepSubscribed(ctx context.Context, wsURL, what string, onReconnect func(), logger *zap.Logger, subscribe func(*ethclient.Client, func()) error) {
+	e := &endpoint{url: wsURL, name: redactURL(wsURL, 0)}
+	connected := false
+	onSubscribed := func() {
+		if connected && onReconnect != nil {
+			onReconnect()
+		}
+		connected = true
+		logger.Info("已订阅"+what, zap.String("endpoint", e.name))
+	}
+
+	for {
+		client, err := ethclient.DialContext(ctx, wsURL)
+		if err != nil {
+			err = fmt.Errorf("连接 WebSocket 节点失败: %w", err)
+		} else {
+			err = subscribe(client, onSubscribed)
+			client.Close()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn(what+"订阅中断，稍后重连", zap.String("endpoint", e.name), zap.Error(e.redact(err)))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// subscriptionClosed 返回订阅中断的原因
+func subscriptionClosed(err error) error {
+	if err == nil {
+		return fmt.Errorf("订阅已被节点关闭")
+	}
+	return err
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// DefaultMulticall3Address Multicall3 的部署地址，Ethereum 和 INK 等链上相同
+const DefaultMulticall3Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+// 批量读取默认参数
+const (
+	DefaultMulticallBatchSize = 100 // 单次 aggregate3 包含的最大调用数
+	maxPrefetchRounds         = 4   // 预取轮数上限，依赖前一次返回值的调用需要额外一轮
+)
+
+// multicall3ABI Multicall3.aggregate3 的 ABI
+const multicall3ABI = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+// errPrefetch 预取阶段未命中缓存的调用返回的错误，调用已加入下一次批量读取
+var errPrefetch = errors.New("调用已加入批量读取")
+
+// MulticallConfig Multicall3 批量读取参数
+type MulticallConfig struct {
+	Address   common.Address // Multicall3 合约地址
+	BatchSize int            // 单次 aggregate3 包含的最大调用数
+	Disabled  bool           // 关闭批量读取，所有调用单独执行
+}
+
+// withDefaults 补全未设置的参数
+func (c MulticallConfig) withDefaults() MulticallConfig {
+	if c.Address == (common.Address{}) {
+		c.Address = common.HexToAddress(DefaultMulticall3Address)
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultMulticallBatchSize
+	}
+	return c
+}
+
+// multicall 一条链的批量读取配置，由同一条链派生的调用器共享
+type multicall struct {
+	cfg         MulticallConfig
+	abi         abi.ABI
+	unavailable atomic.Bool // 链上未部署 Multicall3 时置位，之后不再尝试批量读取
+}
+
+// newMulticall 创建批量读取配置
+func newMulticall(cfg MulticallConfig) *multicall {
+	parsed, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		panic(fmt.Sprintf("解析 Multicall3 ABI 失败: %v", err))
+	}
+	return &multicall{cfg: cfg.withDefaults(), abi: parsed}
+}
+
+// enabled 是否使用批量读取
+func (m *multicall) enabled() bool {
+	return !m.cfg.Disabled && !m.unavailable.Load()
+}
+
+// call3 aggregate3 的单个调用
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// result3 aggregate3 的单个返回值
+type result3 struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// callKey 调用的缓存键
+type callKey struct {
+	to   common.Address
+	data string
+}
+
+// callCache 一轮读取的调用结果缓存
+type callCache struct {
+	mu      sync.Mutex
+	results map[callKey][]byte
+	pending []ethereum.CallMsg // 预取阶段未命中的调用
+	queued  map[callKey]bool
+	collect bool // 是否处于预取阶段
+}
+
+func newCallCache() *callCache {
+	return &callCache{results: make(map[callKey][]byte), queued: make(map[callKey]bool)}
+}
+
+// lookup 查询缓存，预取阶段未命中时将调用加入待读取列表
+func (c *callCache) lookup(msg ethereum.CallMsg) ([]byte, bool, bool) {
+	key := callKey{to: *msg.To, data: string(msg.Data)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if result, ok := c.results[key]; ok {
+		return result, true, false
+	}
+	if !c.collect {
+		return nil, false, false
+	}
+	if !c.queued[key] {
+		c.queued[key] = true
+		c.pending = append(c.pending, msg)
+	}
+	return nil, false, true
+}
+
+// takePending 取出待读取的调用
+func (c *callCache) takePending() []ethereum.CallMsg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending := c.pending
+	c.pending = nil
+	return pending
+}
+
+// store 保存调用结果
+func (c *callCache) store(msg ethereum.CallMsg, result []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[callKey{to: *msg.To, data: string(msg.Data)}] = result
+}
+
+// Prefetch 通过 Multicall3 批量读取 fn 中的所有只读调用，返回使用读取结果的调用器
+//
+// fn 会以预取模式执行多次：未命中缓存的调用返回错误并加入待读取列表，
+// 每轮待读取的调用合并为 aggregate3 读取，直到 fn 不再产生新调用。
+// 返回的调用器优先使用缓存结果，未命中或批量读取中失败的调用单独执行，
+// 因此 revert 等错误仍由单独调用返回完整的错误信息。
+// 链上未部署 Multicall3 时退回单独调用。
+func (c *ContractCaller) Prefetch(ctx context.Context, fn func(*ContractCaller)) *ContractCaller {
+	if c.multicall == nil || !c.multicall.enabled() {
+		return c
+	}
+
+	cache := newCallCache()
+	cache.collect = true
+	collector := c.clone()
+	collector.cache = cache
+
+	for round := 0; round < maxPrefetchRounds; round++ {
+		fn(collector)
+		pending := cache.takePending()
+		if len(pending) == 0 {
+			break
+		}
+		if err := c.aggregate(ctx, pending, cache); err != nil {
+			c.logger.Warn("批量读取失败，退回单独调用", zap.Int("calls", len(pending)), zap.Error(err))
+			break
+		}
+	}
+
+	cache.mu.Lock()
+	cache.collect = false
+	cache.mu.Unlock()
+
+	cached := c.clone()
+	cached.cache = cache
+	return cached
+}
+
+// aggregate 按批次通过 aggregate3 读取调用并保存成功的结果，失败的调用留给单独调用
+func (c *ContractCaller) aggregate(ctx context.Context, msgs []ethereum.CallMsg, cache *callCache) error {
+	mc := c.multicall
+	for start := 0; start < len(msgs); start += mc.cfg.BatchSize {
+		end := min(start+mc.cfg.BatchSize, len(msgs))
+		batch := msgs[start:end]
+
+		calls := make([]call3, len(batch))
+		for i, msg := range batch {
+			calls[i] = call3{Target: *msg.To, AllowFailure: true, CallData: msg.Data}
+		}
+		data, err := mc.abi.Pack("aggregate3", calls)
+		if err != nil {
+			return fmt.Errorf("编码 aggregate3 失败: %w", err)
+		}
+
+		output, err := c.callContract(ctx, ethereum.CallMsg{To: &mc.cfg.Address, Data: data})
+		if err != nil {
+			return fmt.Errorf("调用 aggregate3 失败: %w", err)
+		}
+		if len(output) == 0 {
+			// 目标地址没有合约代码时 eth_call 返回空数据
+			mc.unavailable.Store(true)
+			return fmt.Errorf("Multicall3 未部署: %s", mc.cfg.Address.Hex())
+		}
+
+		var results []result3
+		if err := mc.abi.UnpackIntoInterface(&results, "aggregate3", output); err != nil {
+			return fmt.Errorf("解码 aggregate3 返回值失败: %w", err)
+		}
+		if len(results) != len(batch) {
+			return fmt.Errorf("aggregate3 返回 %d 个结果, 期望 %d 个", len(results), len(batch))
+		}
+		for i, result := range results {
+			if result.Success {
+				cache.store(batch[i], result.ReturnData)
+			}
+		}
+	}
+	return nil
+}
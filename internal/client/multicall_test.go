@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// callArgs eth_call 的参数
+type callArgs struct {
+	To    common.Address `json:"to"`
+	Input hexutil.Bytes  `json:"input"`
+}
+
+// multicallNode 模拟部署了 Multicall3 的节点，调用数据的第一个字节作为返回值，0xff 表示 revert
+type multicallNode struct {
+	deployed   bool
+	aggregates int
+	singles    int
+}
+
+func (n *multicallNode) Call(args callArgs, block interface{}) (hexutil.Bytes, error) {
+	if args.To != common.HexToAddress(DefaultMulticall3Address) {
+		n.singles++
+		if args.Input[0] == 0xff {
+			return nil, revertError{}
+		}
+		return common.LeftPadBytes(args.Input[:1], 32), nil
+	}
+	if !n.deployed {
+		return hexutil.Bytes{}, nil
+	}
+
+	n.aggregates++
+	mc := newMulticall(MulticallConfig{})
+	values, err := mc.abi.Methods["aggregate3"].Inputs.Unpack(args.Input[4:])
+	if err != nil {
+		return nil, err
+	}
+	var calls []call3
+	if err := mc.abi.Methods["aggregate3"].Inputs.Copy(&calls, values); err != nil {
+		return nil, err
+	}
+	results := make([]result3, len(calls))
+	for i, call := range calls {
+		if call.CallData[0] == 0xff {
+			results[i] = result3{Success: false, ReturnData: []byte{}}
+			continue
+		}
+		results[i] = result3{Success: true, ReturnData: common.LeftPadBytes(call.CallData[:1], 32)}
+	}
+	return mc.abi.Methods["aggregate3"].Outputs.Pack(results)
+}
+
+func newMulticallCaller(t *testing.T, node *multicallNode) *ContractCaller {
+	caller, err := NewContractCaller(newFakeNode(t, node), zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(caller.Close)
+	return caller
+}
+
+// readAll 依次读取两个合约，第二次读取的调用数据依赖第一次的返回值
+func readAll(ctx context.Context, c *ContractCaller) (*[3]uint64, error) {
+	var values [3]uint64
+	for i, data := range [][]byte{{0x01}, {0x02}} {
+		v, err := c.CallUint256(ctx, common.HexToAddress("0x01").Hex(), data)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v.Uint64()
+	}
+	v, err := c.CallUint256(ctx, common.HexToAddress("0x02").Hex(), []byte{byte(values[0] + values[1])})
+	if err != nil {
+		return nil, err
+	}
+	values[2] = v.Uint64()
+	return &values, nil
+}
+
+// TestPrefetch 测试通过 aggregate3 批量读取，依赖返回值的调用在下一轮读取
+func TestPrefetch(t *testing.T) {
+	node := &multicallNode{deployed: true}
+	caller := newMulticallCaller(t, node)
+	ctx := context.Background()
+
+	cached := caller.Prefetch(ctx, func(c *ContractCaller) { _, _ = readAll(ctx, c) })
+	assert.Equal(t, 3, node.aggregates)
+	assert.Equal(t, 0, node.singles)
+
+	values, err := readAll(ctx, cached)
+	require.NoError(t, err)
+	assert.Equal(t, [3]uint64{1, 2, 3}, *values)
+	assert.Equal(t, 0, node.singles)
+
+	// 批量读取中失败的调用单独执行，返回完整的错误
+	failed := caller.Prefetch(ctx, func(c *ContractCaller) {
+		_, _ = c.CallUint256(ctx, common.Address{}.Hex(), []byte{0xff})
+	})
+	_, err = failed.CallUint256(ctx, common.Address{}.Hex(), []byte{0xff})
+	assert.ErrorContains(t, err, "execution reverted")
+	assert.Equal(t, 1, node.singles)
+}
+
+// TestPrefetch_NotDeployed 测试未部署 Multicall3 时退回单独调用
+func TestPrefetch_NotDeployed(t *testing.T) {
+	node := &multicallNode{}
+	caller := newMulticallCaller(t, node)
+	ctx := context.Background()
+
+	cached := caller.Prefetch(ctx, func(c *ContractCaller) { _, _ = readAll(ctx, c) })
+	values, err := readAll(ctx, cached)
+	require.NoError(t, err)
+	assert.Equal(t, [3]uint64{1, 2, 3}, *values)
+	assert.Equal(t, 3, node.singles)
+
+	// 之后不再尝试批量读取
+	assert.Same(t, caller, caller.Prefetch(ctx, func(c *ContractCaller) {}))
+}
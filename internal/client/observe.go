@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPC错误分类
+const (
+	ErrorClassTimeout     = "timeout"      // 请求超时
+	ErrorClassCanceled    = "canceled"     // 请求被取消
+	ErrorClassRateLimited = "rate_limited" // 节点限流（HTTP 429 或 JSON-RPC 限流错误）
+	ErrorClassHTTP        = "http"         // 其他 HTTP 错误状态码
+	ErrorClassRPC         = "rpc"          // 节点返回的 JSON-RPC 错误
+	ErrorClassReverted    = "reverted"     // 合约 revert，与节点无关
+	ErrorClassConnection  = "connection"   // 连接失败或中断
+	ErrorClassOther       = "other"
+)
+
+// RPCObserver 接收每次RPC请求的结果，用于导出请求延迟和错误指标
+// class 为 ErrorClass 的分类，请求成功时为空
+type RPCObserver interface {
+	ObserveRPC(chain, endpoint, method string, latency time.Duration, class string)
+}
+
+// ErrorClass 返回RPC错误的分类
+func ErrorClass(err error) string {
+	var (
+		httpErr rpc.HTTPError
+		rpcErr  rpc.Error
+		netErr  net.Error
+		opErr   *net.OpError
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case isDeterministic(err):
+		return ErrorClassReverted
+	case errors.As(err, &httpErr):
+		if httpErr.StatusCode == 429 {
+			return ErrorClassRateLimited
+		}
+		return ErrorClassHTTP
+	case errors.As(err, &rpcErr):
+		if msg := strings.ToLower(rpcErr.Error()); rpcErr.ErrorCode() == -32005 || strings.Contains(msg, "rate limit") || strings.Contains(msg, "too many requests") {
+			return ErrorClassRateLimited
+		}
+		return ErrorClassRPC
+	case errors.As(err, &opErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorClassConnection
+	}
+	return ErrorClassOther
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/viper"
 )
 
@@ -11,11 +14,17 @@ import (
 type Config struct {
 	Log        LogConfig        `mapstructure:"log"`
 	Prometheus PrometheusConfig `mapstructure:"prometheus"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Admin      AdminConfig      `mapstructure:"admin"`
 	Monitor    MonitorConfig    `mapstructure:"monitor"`
 	Contracts  ContractsConfig  `mapstructure:"contracts"`
 	Emergency  EmergencyConfig  `mapstructure:"emergency"`
 	EthRPC     string           `mapstructure:"eth_rpc"`
 	InkRPC     string           `mapstructure:"ink_rpc"`
+	Ethereum   ChainConfig      `mapstructure:"ethereum"` // Ethereum链合约监控配置
+	Ink        ChainConfig      `mapstructure:"ink"`      // INK链合约监控配置
+
+	PriceDeviations []PriceDeviationConfig `mapstructure:"price_deviations"` // 按资产配置的价格偏差检查
 }
 
 // ContractsConfig 合约地址配置（可选）
@@ -45,33 +54,214 @@ type LogConfig struct {
 	Output string `mapstructure:"output"`
 }
 
+// 指标导出方式
+const (
+	PrometheusModePush   = "push"   // 推送到 Prometheus Gateway（默认）
+	PrometheusModeScrape = "scrape" // 通过内置 HTTP 服务的 /metrics 抓取
+	PrometheusModeBoth   = "both"   // 同时推送和抓取
+)
+
 // PrometheusConfig Prometheus配置
 type PrometheusConfig struct {
+	Mode         string `mapstructure:"mode"` // push、scrape 或 both，默认 push
 	GatewayURL   string `mapstructure:"gateway_url"`
 	JobName      string `mapstructure:"job_name"`
 	PushInterval int    `mapstructure:"push_interval"`
 }
 
+// PushEnabled 是否推送指标到 Prometheus Gateway
+func (c *PrometheusConfig) PushEnabled() bool {
+	return c.Mode == "" || c.Mode == PrometheusModePush || c.Mode == PrometheusModeBoth
+}
+
+// ScrapeEnabled 是否通过 /metrics 提供指标抓取
+func (c *PrometheusConfig) ScrapeEnabled() bool {
+	return c.Mode == PrometheusModeScrape || c.Mode == PrometheusModeBoth
+}
+
+// 内置 HTTP 服务默认参数
+const (
+	DefaultListenAddr     = ":8080"
+	DefaultReadyIntervals = 3
+)
+
+// ServerConfig 内置 HTTP 服务配置
+type ServerConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`         // 启动 HTTP 服务（/healthz、/readyz、/status），prometheus.mode 为 scrape 或 both 时总是启动
+	ListenAddr     string `mapstructure:"listen_addr"`     // 监听地址，默认 :8080
+	ReadyIntervals int    `mapstructure:"ready_intervals"` // 任一合约超过该数量的自身检查间隔没有检查成功时 /readyz 失败，默认3
+}
+
+// GetReadyIntervals 获取就绪检查允许的检查间隔数
+func (c *ServerConfig) GetReadyIntervals() int {
+	if c.ReadyIntervals <= 0 {
+		return DefaultReadyIntervals
+	}
+	return c.ReadyIntervals
+}
+
+// GetListenAddr 获取监听地址
+func (c *ServerConfig) GetListenAddr() string {
+	if c.ListenAddr == "" {
+		return DefaultListenAddr
+	}
+	return c.ListenAddr
+}
+
+// 管理接口默认参数
+const (
+	DefaultAdminListenAddr = "127.0.0.1:8081"
+	DefaultAdminAuditLog   = "data/admin_audit.log"
+)
+
+// AdminConfig 应急控制管理接口配置
+type AdminConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`     // 是否启用管理接口
+	ListenAddr string `mapstructure:"listen_addr"` // 监听地址，默认只监听本机 127.0.0.1:8081
+	TokenFile  string `mapstructure:"token_file"`  // Bearer Token 文件
+	TokenEnv   string `mapstructure:"token_env"`   // Bearer Token 环境变量名（未配置 Token 文件时使用）
+	AuditLog   string `mapstructure:"audit_log"`   // 审计日志文件（JSON Lines），默认 data/admin_audit.log
+}
+
+// GetListenAddr 获取管理接口监听地址
+func (c *AdminConfig) GetListenAddr() string {
+	if c.ListenAddr == "" {
+		return DefaultAdminListenAddr
+	}
+	return c.ListenAddr
+}
+
+// GetAuditLog 获取审计日志文件
+func (c *AdminConfig) GetAuditLog() string {
+	if c.AuditLog == "" {
+		return DefaultAdminAuditLog
+	}
+	return c.AuditLog
+}
+
 // MonitorConfig 监控配置
 type MonitorConfig struct {
 	PollInterval int `mapstructure:"poll_interval"`
 	RetryTimes   int `mapstructure:"retry_times"`
 	RetryDelay   int `mapstructure:"retry_delay"`
+	Jitter       int `mapstructure:"jitter"`        // 每次调度增加的随机延迟上限（秒），用于错开检查
+	Concurrency  int `mapstructure:"concurrency"`   // 同时执行的合约检查数，默认8
+	CheckTimeout int `mapstructure:"check_timeout"` // 单个合约检查（含重试）的超时时间（秒），默认等于 poll_interval
+
+	OnFailure string `mapstructure:"on_failure"` // 合约检查（含重试）失败时指标的处理方式: keep（默认）, nan, delete
+
+	Events EventsConfig `mapstructure:"events"` // 事件触发检查
 }
 
+// EventsConfig 事件触发检查配置
+// 监听到合约的暂停/恢复或预言机更新事件时立即检查该合约，定时轮询仍然保留
+type EventsConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	PollInterval  int    `mapstructure:"poll_interval"`   // 未配置 ws_url 的链轮询 eth_getLogs 的间隔（秒），默认2
+	MaxBlockRange uint64 `mapstructure:"max_block_range"` // 单次 eth_getLogs 查询的最大区块数，默认1000
+}
+
+// GetPollInterval 获取 eth_getLogs 轮询间隔，0 表示使用默认值
+func (c *EventsConfig) GetPollInterval() time.Duration {
+	return time.Duration(c.PollInterval) * time.Second
+}
+
+// DefaultConcurrency 默认同时执行的合约检查数
+const DefaultConcurrency = 8
+
 // EmergencyConfig 应急响应配置
 type EmergencyConfig struct {
-	Enabled        bool   `mapstructure:"enabled"`         // 是否启用应急响应
-	PrivateKey     string `mapstructure:"private_key"`     // 委托人私钥
-	SafeAddress    string `mapstructure:"safe_address"`    // Safe多签地址
-	ArgusAddress   string `mapstructure:"argus_address"`   // Argus合约地址
-	WithdrawAmount string `mapstructure:"withdraw_amount"` // 提款金额（wei）
+	Enabled         bool              `mapstructure:"enabled"`          // 是否启用应急响应
+	PrivateKey      string            `mapstructure:"private_key"`      // 委托人私钥（仅 signer.type 为 private_key 时使用）
+	Signer          SignerConfig      `mapstructure:"signer"`           // 交易签名器
+	SafeAddress     string            `mapstructure:"safe_address"`     // Safe多签地址
+	ArgusAddress    string            `mapstructure:"argus_address"`    // Argus合约地址
+	ChainID         int64             `mapstructure:"chain_id"`         // 期望的 INK 链 chain ID，默认 57073，负数表示不校验
+	WithdrawAmount  string            `mapstructure:"withdraw_amount"`  // 提款金额（wei）
+	Rules           []AlertRuleConfig `mapstructure:"rules"`            // 告警规则，未配置时使用内置默认规则
+	Actions         []ActionConfig    `mapstructure:"actions"`          // 应急动作
+	StateFile       string            `mapstructure:"state_file"`       // 触发状态文件，默认 data/emergency_state.json
+	DryRun          bool              `mapstructure:"dry_run"`          // 模拟模式：只执行 eth_call 和 EstimateGas，不广播交易
+	ReceiptTimeout  int               `mapstructure:"receipt_timeout"`  // 每次发送后等待交易回执的时间（秒），默认 120
+	MaxReplacements int               `mapstructure:"max_replacements"` // 交易超时未上链时提价重发的最大次数，默认 3，负数表示不重发
+	FeeBumpPercent  int               `mapstructure:"fee_bump_percent"` // 每次重发提高手续费的百分比，默认 20，最小 10
+	Quorum          QuorumConfig      `mapstructure:"quorum"`           // 触发应急动作的指标的多节点交叉验证
+}
+
+// QuorumConfig 多节点交叉验证配置
+// 关联了应急动作的指标会在同一区块从多个RPC节点读取，达到法定一致数时才执行应急动作
+type QuorumConfig struct {
+	Min       int     `mapstructure:"min"`       // 法定一致数，0 表示不启用
+	Providers int     `mapstructure:"providers"` // 参与读取的节点数，0 表示该链的全部节点
+	Tolerance float64 `mapstructure:"tolerance"` // 数值一致的相对误差，0 表示必须完全相同
+}
+
+// GetReceiptTimeout 获取等待交易回执的时间
+func (c *EmergencyConfig) GetReceiptTimeout() time.Duration {
+	return time.Duration(c.ReceiptTimeout) * time.Second
+}
+
+// SignerConfig 交易签名器配置
+type SignerConfig struct {
+	Type           string `mapstructure:"type"`            // 签名器类型: private_key（默认）, keystore, remote
+	KeystoreFile   string `mapstructure:"keystore_file"`   // keystore: 加密的 keystore 文件
+	PassphraseFile string `mapstructure:"passphrase_file"` // keystore: 密码文件
+	PassphraseEnv  string `mapstructure:"passphrase_env"`  // keystore: 密码环境变量名（未配置密码文件时使用）
+	URL            string `mapstructure:"url"`             // remote: 签名服务 JSON-RPC 地址
+	Address        string `mapstructure:"address"`         // remote: 签名地址
+	Method         string `mapstructure:"method"`          // remote: 签名方法，默认 eth_signTransaction，Clef 使用 account_signTransaction
+}
+
+// ActionConfig 应急动作配置
+type ActionConfig struct {
+	Name        string `mapstructure:"name"`          // 动作名称，供告警规则引用
+	Type        string `mapstructure:"type"`          // 动作类型: gateway_withdraw_eth, bridge_withdraw_weth, repay_debt, notify
+	Amount      string `mapstructure:"amount"`        // 金额（wei）
+	Recipient   string `mapstructure:"recipient"`     // 接收地址/代偿地址，默认Safe
+	MinGasLimit uint32 `mapstructure:"min_gas_limit"` // bridge_withdraw_weth: L1 最小 gas limit
+	Cooldown    int    `mapstructure:"cooldown"`      // 冷却时间（秒），期间任何规则都不会再次执行该动作
+}
+
+// GetCooldown 获取动作冷却时间
+func (a *ActionConfig) GetCooldown() time.Duration {
+	return time.Duration(a.Cooldown) * time.Second
+}
+
+// AlertRuleConfig 告警规则配置
+type AlertRuleConfig struct {
+	Name      string   `mapstructure:"name"`      // 规则名称
+	Metric    string   `mapstructure:"metric"`    // 指标名称，支持通配符 *
+	Operator  string   `mapstructure:"operator"`  // 比较运算符: >, >=, <, <=, ==, !=, change
+	Threshold float64  `mapstructure:"threshold"` // 阈值；change 表示与上一次相比的变化量
+	For       int      `mapstructure:"for"`       // 连续满足条件的轮询次数，默认1
+	Actions   []string `mapstructure:"actions"`   // 命中后执行的应急动作，默认 withdraw_eth
 }
 
 // ChainConfig 链配置
 type ChainConfig struct {
-	RpcURL    string           `mapstructure:"rpc_url"`
-	Contracts []ContractConfig `mapstructure:"contracts"`
+	RpcURL           string           `mapstructure:"rpc_url"`
+	RpcURLs          []string         `mapstructure:"rpc_urls"`           // 备用RPC节点，按优先级排列，排在 eth_rpc/ink_rpc 之后
+	MaxBlockLag      uint64           `mapstructure:"max_block_lag"`      // 节点区块高度落后最高节点超过该值时视为不健康，默认5
+	MaxErrorRate     float64          `mapstructure:"max_error_rate"`     // 节点错误率超过该值时视为不健康，默认0.5
+	MaxLatency       int              `mapstructure:"max_latency"`        // 节点请求延迟（毫秒）超过该值时视为不健康，0表示不限制
+	WsURL            string           `mapstructure:"ws_url"`             // WebSocket 节点，配置后通过 eth_subscribe 订阅事件和新区块，否则轮询
+	EveryBlocks      int              `mapstructure:"every_blocks"`       // 每 N 个新区块检查一次该链的所有合约，0 表示按时间间隔检查
+	HeadPollInterval int              `mapstructure:"head_poll_interval"` // 未配置 ws_url 时轮询 eth_blockNumber 的间隔（毫秒），默认1000
+	Contracts        []ContractConfig `mapstructure:"contracts"`
+
+	MulticallAddress   string `mapstructure:"multicall_address"`    // Multicall3 合约地址，为空时使用标准部署地址
+	MulticallBatchSize int    `mapstructure:"multicall_batch_size"` // 单次 aggregate3 包含的最大调用数，默认100
+	DisableMulticall   bool   `mapstructure:"disable_multicall"`    // 关闭批量读取，每个调用单独执行
+}
+
+// GetHeadPollInterval 获取轮询 eth_blockNumber 的间隔，0 表示使用默认值
+func (c *ChainConfig) GetHeadPollInterval() time.Duration {
+	return time.Duration(c.HeadPollInterval) * time.Millisecond
+}
+
+// GetMaxLatency 获取节点最大请求延迟
+func (c *ChainConfig) GetMaxLatency() time.Duration {
+	return time.Duration(c.MaxLatency) * time.Millisecond
 }
 
 // ContractConfig 合约配置
@@ -81,9 +271,128 @@ type ContractConfig struct {
 	Type         string        `mapstructure:"type"`
 	Method       string        `mapstructure:"method"`
 	MethodParams []interface{} `mapstructure:"method_params"`
+	Decimals     int           `mapstructure:"decimals"`  // 返回值精度（价格源默认8位，latestRoundData 默认读取 decimals()）
+	Heartbeat    int           `mapstructure:"heartbeat"` // price_feed (latestRoundData): 心跳间隔（秒），超过该时间未更新视为过期，默认3600
+	Signature    string        `mapstructure:"signature"` // abi_call: 方法签名，如 "getReserveCaps(address) returns (uint256,uint256)"
+	ABI          string        `mapstructure:"abi"`       // abi_call: ABI片段（JSON），与 signature 二选一
+	Output       string        `mapstructure:"output"`    // abi_call: 作为指标值的返回字段名称或下标
+	Scale        *float64      `mapstructure:"scale"`     // abi_call: 换算后的缩放系数，必须大于0，默认1
 	Alert        *AlertConfig  `mapstructure:"alert"`
+
+	PollInterval int    `mapstructure:"poll_interval"` // 检查间隔（秒），默认 monitor.poll_interval
+	Jitter       int    `mapstructure:"jitter"`        // 每次调度增加的随机延迟上限（秒），默认 monitor.jitter
+	OnFailure    string `mapstructure:"on_failure"`    // 检查失败时指标的处理方式，默认 monitor.on_failure
+
+	Events       []string `mapstructure:"events"`        // 触发立即检查的事件签名，如 "Paused(address)"，默认按合约类型选择
+	EventAddress string   `mapstructure:"event_address"` // 发出事件的合约地址，默认为 address
+}
+
+// GetPollInterval 获取合约的检查间隔，未配置时返回 def
+func (c *ContractConfig) GetPollInterval(def time.Duration) time.Duration {
+	if c.PollInterval <= 0 {
+		return def
+	}
+	return time.Duration(c.PollInterval) * time.Second
+}
+
+// GetJitter 获取合约检查的随机延迟上限，未配置时返回 def
+func (c *ContractConfig) GetJitter(def time.Duration) time.Duration {
+	if c.Jitter <= 0 {
+		return def
+	}
+	return time.Duration(c.Jitter) * time.Second
 }
 
+// 合约检查失败时指标的处理方式
+const (
+	OnFailureKeep   = "keep"   // 保留上一次成功读取的值
+	OnFailureNaN    = "nan"    // 设置为 NaN，面板上显示为无数据
+	OnFailureDelete = "delete" // 删除指标，不再推送和抓取，检查成功后恢复
+)
+
+// validMetricName 检查合约名称能否用于指标名称（字母、数字和下划线）
+func validMetricName(name string) bool {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// validOnFailure 检查失败处理方式是否有效，空值表示使用默认值
+func validOnFailure(policy string) bool {
+	switch policy {
+	case "", OnFailureKeep, OnFailureNaN, OnFailureDelete:
+		return true
+	}
+	return false
+}
+
+// PriceDeviationConfig 价格偏差检查：将监控的价格源与一个或多个参考价格源的中位数比较
+type PriceDeviationConfig struct {
+	Asset         string                `mapstructure:"asset"`          // 资产名称，如 ETH、wstETH、USDC，用于指标名称
+	Feed          string                `mapstructure:"feed"`           // 被检查的价格源（ethereum 或 ink 上监控合约的名称）
+	Threshold     float64               `mapstructure:"threshold"`      // 偏差超过该值时记录警告，如 0.05 表示 5%，0 表示不记录
+	MinReferences int                   `mapstructure:"min_references"` // 至少成功读取的参考价格源数量，默认1
+	References    []ReferenceFeedConfig `mapstructure:"references"`
+}
+
+// ReferenceFeedConfig 参考价格源，引用监控合约（name）或直接指定链和地址
+type ReferenceFeedConfig struct {
+	Name     string `mapstructure:"name"`     // 监控合约名称，与 chain/address 二选一
+	Chain    string `mapstructure:"chain"`    // ethereum 或 ink
+	Address  string `mapstructure:"address"`  // 价格源地址
+	Method   string `mapstructure:"method"`   // 读取价格的方法，默认 latestRoundData
+	Decimals int    `mapstructure:"decimals"` // 价格精度，latestRoundData 默认读取 decimals()，latestAnswer 默认8位
+}
+
+// GetMinReferences 获取至少成功读取的参考价格源数量
+func (c *PriceDeviationConfig) GetMinReferences() int {
+	if c.MinReferences <= 0 {
+		return 1
+	}
+	return c.MinReferences
+}
+
+// validate 验证价格偏差检查配置
+func (c *PriceDeviationConfig) validate(prefix string) error {
+	if c.Asset == "" {
+		return fmt.Errorf("%s.asset 不能为空", prefix)
+	}
+	if c.Feed == "" {
+		return fmt.Errorf("%s.feed 不能为空", prefix)
+	}
+	if c.Threshold < 0 {
+		return fmt.Errorf("%s.threshold 不能为负数", prefix)
+	}
+	if len(c.References) == 0 {
+		return fmt.Errorf("%s.references 不能为空", prefix)
+	}
+	if c.GetMinReferences() > len(c.References) {
+		return fmt.Errorf("%s.min_references (%d) 不能大于参考价格源数量 (%d)", prefix, c.MinReferences, len(c.References))
+	}
+	for i, ref := range c.References {
+		refPrefix := fmt.Sprintf("%s.references[%d]", prefix, i)
+		switch {
+		case ref.Name != "" && (ref.Chain != "" || ref.Address != ""):
+			return fmt.Errorf("%s 的 name 和 chain/address 只能配置一个", refPrefix)
+		case ref.Name != "":
+		case ref.Chain != "ethereum" && ref.Chain != "ink":
+			return fmt.Errorf("%s.chain 必须为 ethereum 或 ink: %q", refPrefix, ref.Chain)
+		case !common.IsHexAddress(ref.Address):
+			return fmt.Errorf("%s.address 不是有效地址: %q", refPrefix, ref.Address)
+		}
+	}
+	return nil
+}
+
+// 告警类型常量
+const (
+	AlertTypePriceDiff  = "price_diff"
+	AlertTypeSupplyDiff = "supply_diff"
+)
+
 // AlertConfig 告警配置
 type AlertConfig struct {
 	Type           string      `mapstructure:"type"`
@@ -112,6 +421,26 @@ func (a *AlertConfig) GetThresholdString() string {
 	return ""
 }
 
+// ParseThreshold 解析数值或字符串形式的阈值，未配置或无法解析时返回错误
+func (a *AlertConfig) ParseThreshold() (float64, error) {
+	switch v := a.Threshold.(type) {
+	case nil:
+		return 0, fmt.Errorf("不能为空")
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case string:
+		t, ok := new(big.Float).SetString(v)
+		if !ok {
+			return 0, fmt.Errorf("无法解析: %q", v)
+		}
+		threshold, _ := t.Float64()
+		return threshold, nil
+	}
+	return 0, fmt.Errorf("类型不支持: %T", a.Threshold)
+}
+
 var globalConfig *Config
 
 // Load 加载配置文件
@@ -133,6 +462,20 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
+	// 兼容 ethereum.rpc_url / ink.rpc_url 写法，只配置了 rpc_urls 时使用第一个节点
+	if cfg.EthRPC == "" {
+		cfg.EthRPC = cfg.Ethereum.RpcURL
+	}
+	if cfg.EthRPC == "" && len(cfg.Ethereum.RpcURLs) > 0 {
+		cfg.EthRPC = cfg.Ethereum.RpcURLs[0]
+	}
+	if cfg.InkRPC == "" {
+		cfg.InkRPC = cfg.Ink.RpcURL
+	}
+	if cfg.InkRPC == "" && len(cfg.Ink.RpcURLs) > 0 {
+		cfg.InkRPC = cfg.Ink.RpcURLs[0]
+	}
+
 	// 验证配置
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("配置验证失败: %w", err)
@@ -142,6 +485,30 @@ func Load(configPath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// GetEthereumRPCs 获取Ethereum的全部RPC节点，eth_rpc 优先
+func (c *Config) GetEthereumRPCs() []string {
+	return rpcURLs(c.EthRPC, c.Ethereum)
+}
+
+// GetInkRPCs 获取INK的全部RPC节点，ink_rpc 优先
+func (c *Config) GetInkRPCs() []string {
+	return rpcURLs(c.InkRPC, c.Ink)
+}
+
+// rpcURLs 按优先级合并主节点和备用节点并去重
+func rpcURLs(primary string, chain ChainConfig) []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, url := range append([]string{primary, chain.RpcURL}, chain.RpcURLs...) {
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		urls = append(urls, url)
+	}
+	return urls
+}
+
 // Get 获取全局配置
 func Get() *Config {
 	return globalConfig
@@ -155,12 +522,119 @@ func (c *Config) Validate() error {
 	if c.InkRPC == "" {
 		return fmt.Errorf("ink_rpc 不能为空")
 	}
-	if c.Prometheus.GatewayURL == "" {
-		return fmt.Errorf("prometheus.gateway_url 不能为空")
+	switch c.Prometheus.Mode {
+	case "", PrometheusModePush, PrometheusModeScrape, PrometheusModeBoth:
+	default:
+		return fmt.Errorf("prometheus.mode 不支持: %q", c.Prometheus.Mode)
+	}
+	if c.Prometheus.PushEnabled() && c.Prometheus.GatewayURL == "" {
+		return fmt.Errorf("prometheus.gateway_url 不能为空（仅抓取时请配置 prometheus.mode: scrape）")
 	}
 	if c.Monitor.PollInterval <= 0 {
 		return fmt.Errorf("monitor.poll_interval 必须大于0")
 	}
+	if !validOnFailure(c.Monitor.OnFailure) {
+		return fmt.Errorf("monitor.on_failure 不支持: %q", c.Monitor.OnFailure)
+	}
+	if c.Admin.Enabled && c.Admin.TokenFile == "" && c.Admin.TokenEnv == "" {
+		return fmt.Errorf("admin 启用时需要配置 token_file 或 token_env")
+	}
+	if err := c.Ethereum.validate("ethereum"); err != nil {
+		return err
+	}
+	if err := c.Ink.validate("ink"); err != nil {
+		return err
+	}
+	assets := make(map[string]bool)
+	for i, deviation := range c.PriceDeviations {
+		prefix := fmt.Sprintf("price_deviations[%d]", i)
+		if err := deviation.validate(prefix); err != nil {
+			return err
+		}
+		asset := strings.ToLower(deviation.Asset)
+		if assets[asset] {
+			return fmt.Errorf("%s.asset 重复: %s", prefix, deviation.Asset)
+		}
+		assets[asset] = true
+	}
+	return nil
+}
+
+// validate 验证链上合约配置
+func (c *ChainConfig) validate(chain string) error {
+	if c.EveryBlocks < 0 {
+		return fmt.Errorf("%s.every_blocks 不能为负数", chain)
+	}
+	if c.MulticallAddress != "" && !common.IsHexAddress(c.MulticallAddress) {
+		return fmt.Errorf("%s.multicall_address 不是有效地址: %q", chain, c.MulticallAddress)
+	}
+	names := make(map[string]bool)
+	for i, contract := range c.Contracts {
+		prefix := fmt.Sprintf("%s.contracts[%d]", chain, i)
+		if contract.Name == "" {
+			return fmt.Errorf("%s.name 不能为空", prefix)
+		}
+		if !validMetricName(contract.Name) {
+			return fmt.Errorf("%s.name 只能包含字母、数字和下划线: %q", prefix, contract.Name)
+		}
+		if names[contract.Name] {
+			return fmt.Errorf("%s.name 重复: %s", prefix, contract.Name)
+		}
+		names[contract.Name] = true
+		if !common.IsHexAddress(contract.Address) {
+			return fmt.Errorf("%s.address 不是有效地址: %q", prefix, contract.Address)
+		}
+		if contract.Type == "" {
+			return fmt.Errorf("%s.type 不能为空", prefix)
+		}
+		if contract.Decimals < 0 {
+			return fmt.Errorf("%s.decimals 不能为负数（合约 %s）: %d", prefix, contract.Name, contract.Decimals)
+		}
+		if contract.Scale != nil && *contract.Scale <= 0 {
+			return fmt.Errorf("%s.scale 必须大于0（合约 %s）: %v", prefix, contract.Name, *contract.Scale)
+		}
+		if contract.PollInterval < 0 || contract.Jitter < 0 {
+			return fmt.Errorf("%s.poll_interval 和 jitter 不能为负数", prefix)
+		}
+		if !validOnFailure(contract.OnFailure) {
+			return fmt.Errorf("%s.on_failure 不支持: %q", prefix, contract.OnFailure)
+		}
+		if contract.EventAddress != "" && !common.IsHexAddress(contract.EventAddress) {
+			return fmt.Errorf("%s.event_address 不是有效地址: %q", prefix, contract.EventAddress)
+		}
+		if contract.Alert != nil {
+			if err := contract.Alert.validate(prefix + ".alert"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validate 验证告警配置
+func (a *AlertConfig) validate(prefix string) error {
+	switch a.Type {
+	case AlertTypePriceDiff:
+		if a.CompareWith == "" {
+			return fmt.Errorf("%s.compare_with 不能为空", prefix)
+		}
+		if a.GetThresholdFloat() <= 0 {
+			return fmt.Errorf("%s.threshold 必须大于0", prefix)
+		}
+	case AlertTypeSupplyDiff:
+		if !common.IsHexAddress(a.CompareAddress) {
+			return fmt.Errorf("%s.compare_address 不是有效地址: %q", prefix, a.CompareAddress)
+		}
+		threshold, err := a.ParseThreshold()
+		if err != nil {
+			return fmt.Errorf("%s.threshold %w", prefix, err)
+		}
+		if threshold < 0 {
+			return fmt.Errorf("%s.threshold 不能为负数", prefix)
+		}
+	default:
+		return fmt.Errorf("%s.type 不支持: %q", prefix, a.Type)
+	}
 	return nil
 }
 
@@ -169,6 +643,35 @@ func (c *MonitorConfig) GetPollDuration() time.Duration {
 	return time.Duration(c.PollInterval) * time.Second
 }
 
+// GetJitter 获取检查调度的随机延迟上限
+func (c *MonitorConfig) GetJitter() time.Duration {
+	return time.Duration(c.Jitter) * time.Second
+}
+
+// GetOnFailure 获取合约检查失败时指标的处理方式
+func (c *MonitorConfig) GetOnFailure() string {
+	if c.OnFailure == "" {
+		return OnFailureKeep
+	}
+	return c.OnFailure
+}
+
+// GetConcurrency 获取同时执行的合约检查数
+func (c *MonitorConfig) GetConcurrency() int {
+	if c.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return c.Concurrency
+}
+
+// GetCheckTimeout 获取单个合约检查的超时时间，未配置时等于轮询间隔
+func (c *MonitorConfig) GetCheckTimeout() time.Duration {
+	if c.CheckTimeout <= 0 {
+		return c.GetPollDuration()
+	}
+	return time.Duration(c.CheckTimeout) * time.Second
+}
+
 // GetRetryDelay 获取重试延迟时间
 func (c *MonitorConfig) GetRetryDelay() time.Duration {
 	return time.Duration(c.RetryDelay) * time.Second
@@ -0,0 +1,405 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+)
+
+// ABICall 通用只读调用监控
+// 通过 accounts/abi 编码参数、解码返回值，并将选定的返回字段换算为指标值：
+//
+//	value = raw / 10^decimals * scale
+type ABICall struct {
+	BaseContract
+	method   abi.Method
+	params   []interface{}
+	output   int
+	decimals int
+	scale    float64
+}
+
+// ABICallOptions ABICall 的可选参数
+type ABICallOptions struct {
+	Output   string  // 返回字段名称或下标，默认第一个
+	Decimals int     // 返回值精度，不能为负数
+	Scale    float64 // 换算后的缩放系数，0 表示 1，不能为负数
+}
+
+// NewABICall 创建 ABICall 实例，params 为配置中的原始参数，会按方法的输入类型转换
+func NewABICall(name string, address common.Address, method abi.Method, params []interface{}, opts ABICallOptions) (*ABICall, error) {
+	if len(method.Outputs) == 0 {
+		return nil, fmt.Errorf("方法 %s 没有返回值", method.Sig)
+	}
+	if opts.Decimals < 0 {
+		return nil, fmt.Errorf("合约 %s 的 decimals 不能为负数: %d", name, opts.Decimals)
+	}
+	if opts.Scale < 0 {
+		return nil, fmt.Errorf("合约 %s 的 scale 不能为负数: %v", name, opts.Scale)
+	}
+	if len(params) != len(method.Inputs) {
+		return nil, fmt.Errorf("方法 %s 需要 %d 个参数, 实际 %d 个", method.Sig, len(method.Inputs), len(params))
+	}
+
+	converted := make([]interface{}, len(params))
+	for i, param := range params {
+		v, err := convertParam(method.Inputs[i].Type, param)
+		if err != nil {
+			return nil, fmt.Errorf("参数 %d (%s): %w", i, method.Inputs[i].Type, err)
+		}
+		converted[i] = v
+	}
+
+	output, err := outputIndex(method.Outputs, opts.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 1
+	}
+
+	return &ABICall{
+		BaseContract: NewBaseContract(name, address, TypeABICall),
+		method:       method,
+		params:       converted,
+		output:       output,
+		decimals:     opts.Decimals,
+		scale:        scale,
+	}, nil
+}
+
+// Calldata 返回编码后的调用数据
+func (c *ABICall) Calldata() ([]byte, error) {
+	args, err := c.method.Inputs.Pack(c.params...)
+	if err != nil {
+		return nil, fmt.Errorf("编码参数失败: %w", err)
+	}
+	return append(append([]byte{}, c.method.ID...), args...), nil
+}
+
+// Decode 解码返回数据并换算为指标值
+func (c *ABICall) Decode(result []byte) (float64, error) {
+	values, err := c.method.Outputs.Unpack(result)
+	if err != nil {
+		return 0, fmt.Errorf("解码返回值失败: %w", err)
+	}
+	if c.output >= len(values) {
+		return 0, fmt.Errorf("返回值数量不足: %d", len(values))
+	}
+
+	raw, err := toBigFloat(values[c.output])
+	if err != nil {
+		return 0, err
+	}
+
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.decimals)), nil))
+	raw.Quo(raw, divisor)
+	raw.Mul(raw, new(big.Float).SetFloat64(c.scale))
+	value, _ := raw.Float64()
+
+	return value, nil
+}
+
+// Monitor 执行调用并返回指标值
+func (c *ABICall) Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error) {
+	data, err := c.Calldata()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := caller.CallRaw(ctx, c.address.Hex(), data)
+	if err != nil {
+		return 0, fmt.Errorf("调用 %s 失败: %w", c.method.Sig, err)
+	}
+
+	return c.Decode(result)
+}
+
+// ParseMethodSignature 解析方法签名，例如：
+//
+//	getReserveCaps(address) returns (uint256 borrowCap, uint256 supplyCap)
+//	latestAnswer()(int256)
+//
+// 不支持 tuple 类型，需要 tuple 时请使用 ParseMethodABI
+func ParseMethodSignature(signature string) (abi.Method, error) {
+	sig := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(signature), "function "))
+
+	open := strings.Index(sig, "(")
+	if open <= 0 {
+		return abi.Method{}, fmt.Errorf("方法签名格式错误: %q", signature)
+	}
+	name := strings.TrimSpace(sig[:open])
+
+	inputsRaw, rest, err := splitParens(sig[open:])
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("方法签名格式错误: %q: %w", signature, err)
+	}
+
+	// 跳过 returns 之前的修饰符（view、external 等）
+	var outputsRaw string
+	if idx := strings.Index(rest, "("); idx >= 0 {
+		outputsRaw, rest, err = splitParens(rest[idx:])
+		if err != nil {
+			return abi.Method{}, fmt.Errorf("方法签名格式错误: %q: %w", signature, err)
+		}
+	}
+	if strings.TrimSpace(rest) != "" {
+		return abi.Method{}, fmt.Errorf("方法签名格式错误: %q", signature)
+	}
+
+	inputs, err := parseArguments(inputsRaw)
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("解析输入参数失败: %w", err)
+	}
+	outputs, err := parseArguments(outputsRaw)
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("解析返回值失败: %w", err)
+	}
+
+	return abi.NewMethod(name, name, abi.Function, "view", true, false, inputs, outputs), nil
+}
+
+// ParseMethodABI 从 ABI 片段中解析方法，片段可以是单个函数对象或数组
+// 片段中只有一个函数时 name 可以为空
+func ParseMethodABI(fragment, name string) (abi.Method, error) {
+	fragment = strings.TrimSpace(fragment)
+	if strings.HasPrefix(fragment, "{") {
+		fragment = "[" + fragment + "]"
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(fragment))
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("解析ABI失败: %w", err)
+	}
+
+	if name == "" {
+		if len(parsed.Methods) != 1 {
+			return abi.Method{}, fmt.Errorf("ABI包含 %d 个方法，需要指定 method", len(parsed.Methods))
+		}
+		for _, method := range parsed.Methods {
+			return method, nil
+		}
+	}
+
+	method, ok := parsed.Methods[name]
+	if !ok {
+		return abi.Method{}, fmt.Errorf("ABI中不存在方法: %s", name)
+	}
+	return method, nil
+}
+
+// splitParens 拆分以 "(" 开头的字符串，返回括号内的内容和剩余部分
+func splitParens(s string) (string, string, error) {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], s[i+1:], nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("括号不匹配")
+}
+
+// parseArguments 解析逗号分隔的参数列表，每个参数格式为 "type [name]"
+func parseArguments(raw string) (abi.Arguments, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return abi.Arguments{}, nil
+	}
+
+	var args abi.Arguments
+	for i, part := range strings.Split(raw, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("参数 %d 格式错误: %q", i, part)
+		}
+		typ, err := abi.NewType(fields[0], "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("参数 %d 类型错误: %w", i, err)
+		}
+		arg := abi.Argument{Type: typ}
+		if len(fields) == 2 {
+			arg.Name = fields[1]
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// outputIndex 根据字段名称或下标查找返回值位置
+func outputIndex(outputs abi.Arguments, output string) (int, error) {
+	if output == "" {
+		return 0, nil
+	}
+	if idx, err := strconv.Atoi(output); err == nil {
+		if idx < 0 || idx >= len(outputs) {
+			return 0, fmt.Errorf("返回值下标越界: %d", idx)
+		}
+		return idx, nil
+	}
+	for i, arg := range outputs {
+		if arg.Name == output {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("返回值中不存在字段: %s", output)
+}
+
+// convertParam 将配置中的参数转换为 abi 编码需要的 Go 类型
+func convertParam(t abi.Type, v interface{}) (interface{}, error) {
+	switch t.T {
+	case abi.AddressTy:
+		s, ok := v.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("不是有效地址: %v", v)
+		}
+		return common.HexToAddress(s), nil
+
+	case abi.UintTy, abi.IntTy:
+		n, err := toBigInt(v)
+		if err != nil {
+			return nil, err
+		}
+		if !fitsInt(n, t) {
+			return nil, fmt.Errorf("数值超出 %s 范围: %v", t, v)
+		}
+		if t.Size > 64 {
+			return n, nil
+		}
+		if t.T == abi.UintTy {
+			return reflect.ValueOf(n.Uint64()).Convert(t.GetType()).Interface(), nil
+		}
+		return reflect.ValueOf(n.Int64()).Convert(t.GetType()).Interface(), nil
+
+	case abi.BoolTy:
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			return strconv.ParseBool(b)
+		}
+		return nil, fmt.Errorf("不是有效布尔值: %v", v)
+
+	case abi.StringTy:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("不是字符串: %v", v)
+		}
+		return s, nil
+
+	case abi.BytesTy, abi.FixedBytesTy:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("不是十六进制字符串: %v", v)
+		}
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("不是十六进制字符串: %w", err)
+		}
+		if t.T == abi.BytesTy {
+			return b, nil
+		}
+		if len(b) > t.Size {
+			return nil, fmt.Errorf("长度超过 %d 字节: %s", t.Size, s)
+		}
+		arr := reflect.New(t.GetType()).Elem()
+		reflect.Copy(arr, reflect.ValueOf(common.LeftPadBytes(b, t.Size)))
+		return arr.Interface(), nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		items, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("不是数组: %v", v)
+		}
+		if t.T == abi.ArrayTy && len(items) != t.Size {
+			return nil, fmt.Errorf("数组长度应为 %d, 实际 %d", t.Size, len(items))
+		}
+		var out reflect.Value
+		if t.T == abi.ArrayTy {
+			out = reflect.New(t.GetType()).Elem()
+		} else {
+			out = reflect.MakeSlice(t.GetType(), len(items), len(items))
+		}
+		for i, item := range items {
+			elem, err := convertParam(*t.Elem, item)
+			if err != nil {
+				return nil, fmt.Errorf("元素 %d: %w", i, err)
+			}
+			out.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return out.Interface(), nil
+	}
+
+	return nil, fmt.Errorf("不支持的参数类型: %s", t)
+}
+
+// fitsInt 检查整数是否在 uintN/intN 的取值范围内
+func fitsInt(n *big.Int, t abi.Type) bool {
+	if t.T == abi.UintTy {
+		return n.Sign() >= 0 && n.BitLen() <= t.Size
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	return n.Cmp(limit) < 0 && n.Cmp(new(big.Int).Neg(limit)) >= 0
+}
+
+// toBigInt 将配置中的数值（整数、浮点整数、十进制或0x十六进制字符串）转换为 big.Int
+func toBigInt(v interface{}) (*big.Int, error) {
+	switch n := v.(type) {
+	case int:
+		return big.NewInt(int64(n)), nil
+	case int64:
+		return big.NewInt(n), nil
+	case uint64:
+		return new(big.Int).SetUint64(n), nil
+	case float64:
+		if n != float64(int64(n)) {
+			return nil, fmt.Errorf("不是整数: %v", n)
+		}
+		return big.NewInt(int64(n)), nil
+	case string:
+		i, ok := new(big.Int).SetString(n, 0)
+		if !ok {
+			return nil, fmt.Errorf("不是有效整数: %s", n)
+		}
+		return i, nil
+	}
+	return nil, fmt.Errorf("不是有效整数: %v", v)
+}
+
+// toBigFloat 将解码后的返回值转换为 big.Float
+func toBigFloat(v interface{}) (*big.Float, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		return new(big.Float).SetInt(n), nil
+	case bool:
+		if n {
+			return big.NewFloat(1), nil
+		}
+		return big.NewFloat(0), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Float).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("返回值类型无法转换为数值: %T", v)
+}
@@ -0,0 +1,93 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseMethodSignature 测试方法签名解析
+func TestParseMethodSignature(t *testing.T) {
+	method, err := ParseMethodSignature("getReserveCaps(address) returns (uint256 borrowCap, uint256 supplyCap)")
+	require.NoError(t, err)
+	assert.Equal(t, "getReserveCaps(address)", method.Sig)
+	assert.Equal(t, crypto.Keccak256([]byte("getReserveCaps(address)"))[:4], method.ID)
+	require.Len(t, method.Outputs, 2)
+	assert.Equal(t, "supplyCap", method.Outputs[1].Name)
+
+	method, err = ParseMethodSignature("function latestAnswer() external view returns (int256)")
+	require.NoError(t, err)
+	assert.Equal(t, "latestAnswer()", method.Sig)
+	require.Len(t, method.Outputs, 1)
+
+	_, err = ParseMethodSignature("paused(")
+	assert.Error(t, err)
+	_, err = ParseMethodSignature("paused(foo)(bool)")
+	assert.Error(t, err)
+}
+
+// TestABICall_EncodeDecode 测试 ABICall 的编码与换算
+func TestABICall_EncodeDecode(t *testing.T) {
+	method, err := ParseMethodSignature("getReserveCaps(address)(uint256 borrowCap,uint256 supplyCap)")
+	require.NoError(t, err)
+
+	call, err := NewABICall("weth_supply_cap", common.HexToAddress(DefaultL2AaveProtocolDataProvider), method,
+		[]interface{}{L2WETH}, ABICallOptions{Output: "supplyCap", Decimals: 3, Scale: 2})
+	require.NoError(t, err)
+	assert.Equal(t, TypeABICall, call.Type())
+
+	data, err := call.Calldata()
+	require.NoError(t, err)
+	want := append(crypto.Keccak256([]byte("getReserveCaps(address)"))[:4], common.LeftPadBytes(common.HexToAddress(L2WETH).Bytes(), 32)...)
+	assert.Equal(t, want, data)
+
+	result, err := method.Outputs.Pack(big.NewInt(100), big.NewInt(12500))
+	require.NoError(t, err)
+	value, err := call.Decode(result)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, value) // 12500 / 10^3 * 2
+}
+
+// TestABICall_ParamConversion 测试配置参数按类型转换
+func TestABICall_ParamConversion(t *testing.T) {
+	method, err := ParseMethodSignature("quote(uint8,int256,bool,bytes32,address[])(bool)")
+	require.NoError(t, err)
+
+	call, err := NewABICall("quote", common.Address{}, method, []interface{}{
+		6, "-0x10", "true", "0x01", []interface{}{L2WETH, L1WETH},
+	}, ABICallOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), call.params[0])
+	assert.Equal(t, big.NewInt(-16), call.params[1])
+	assert.Equal(t, true, call.params[2])
+	assert.Equal(t, [32]byte{31: 1}, call.params[3])
+
+	_, err = call.Calldata()
+	require.NoError(t, err)
+
+	result, err := method.Outputs.Pack(true)
+	require.NoError(t, err)
+	value, err := call.Decode(result)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, value)
+
+	_, err = NewABICall("quote", common.Address{}, method, []interface{}{256, 0, true, "0x", []interface{}{}}, ABICallOptions{})
+	assert.Error(t, err, "uint8 溢出应报错")
+	_, err = NewABICall("quote", common.Address{}, method, []interface{}{1}, ABICallOptions{})
+	assert.Error(t, err, "参数数量不符应报错")
+}
+
+// TestParseMethodABI 测试从 ABI 片段解析方法
+func TestParseMethodABI(t *testing.T) {
+	fragment := `{"type":"function","name":"decimals","inputs":[],"outputs":[{"name":"","type":"uint8"}],"stateMutability":"view"}`
+	method, err := ParseMethodABI(fragment, "")
+	require.NoError(t, err)
+	assert.Equal(t, "decimals()", method.Sig)
+
+	_, err = ParseMethodABI(fragment, "latestAnswer")
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package contracts
+
+import (
+	_ "embed"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed abis/l2_standard_bridge.abi.json
+var l2StandardBridgeABI string
+
+//go:embed abis/weth.abi.json
+var wethABI string
+
+var (
+	L2StandardBridge = common.HexToAddress("0x4200000000000000000000000000000000000010")
+	LegacyERC20ETH   = common.HexToAddress("0xDeadDeAddeAddEAddeadDEaDDEAdDeaDDeAD0000")
+)
+
+// DefaultBridgeMinGasLimit L2->L1 提款默认的最小 gas limit
+const DefaultBridgeMinGasLimit = 200000
+
+func buildWETHWithdraw(amount *big.Int) ([]byte, error) {
+	weth, err := abi.JSON(strings.NewReader(wethABI))
+	if err != nil {
+		return nil, err
+	}
+	data, err := weth.Pack("withdraw", amount)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func buildL2BridgeWithdrawTo(l2Token, to common.Address, amount *big.Int, minGasLimit uint32, extraData []byte) ([]byte, error) {
+	bridge, err := abi.JSON(strings.NewReader(l2StandardBridgeABI))
+	if err != nil {
+		return nil, err
+	}
+	data, err := bridge.Pack("withdrawTo", l2Token, to, amount, minGasLimit, extraData)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// BuildBridgeWithdrawWETHCalls 构造通过 L2StandardBridge 将 WETH 提回 L1 的调用：
+// 先将 WETH 解包为 ETH，再以 ETH 形式 withdrawTo 到 L1 的接收地址
+func BuildBridgeWithdrawWETHCalls(amount *big.Int, to common.Address, minGasLimit uint32) ([]Call, error) {
+	unwrapData, err := buildWETHWithdraw(amount)
+	if err != nil {
+		return nil, err
+	}
+	withdrawData, err := buildL2BridgeWithdrawTo(LegacyERC20ETH, to, amount, minGasLimit, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return []Call{
+		{To: WETH, Value: big.NewInt(0), Data: unwrapData},
+		{To: L2StandardBridge, Value: new(big.Int).Set(amount), Data: withdrawData},
+	}, nil
+}
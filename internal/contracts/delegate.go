@@ -2,16 +2,15 @@ package contracts
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
-	"log"
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
 )
 
 var (
@@ -21,134 +20,131 @@ var (
 	WETH           = common.HexToAddress("0x4200000000000000000000000000000000000006")
 )
 
+// InkChainID Ink 主网 chain ID
+const InkChainID = 57073
+
 type Delegate struct {
-	client     *ethclient.Client
-	bot        common.Address
-	privateKey *ecdsa.PrivateKey
-	safe       common.Address
-	argus      common.Address
+	client *ethclient.Client
+	bot    common.Address
+	signer Signer
+	safe   common.Address
+	argus  common.Address
+	logger *zap.Logger
 }
 
-func NewDelegate(rpcUrl, delegatePrivateKey, safe, argus string) *Delegate {
-	client, err := ethclient.Dial(rpcUrl)
-	if err != nil {
-		log.Fatal(err)
+// NewDelegate 创建委托人并校验运行环境
+// chainID 不为 0 时校验节点的 chain ID，同时校验 bot 地址是 Argus 的授权委托人
+func NewDelegate(ctx context.Context, rpcUrl string, signer Signer, safe, argus string, chainID uint64, logger *zap.Logger) (*Delegate, error) {
+	if !common.IsHexAddress(safe) {
+		return nil, fmt.Errorf("safe 地址无效: %q", safe)
 	}
-	privateKey, err := crypto.HexToECDSA(delegatePrivateKey)
-	if err != nil {
-		log.Fatal(err)
+	if !common.IsHexAddress(argus) {
+		return nil, fmt.Errorf("argus 地址无效: %q", argus)
 	}
-	address := crypto.PubkeyToAddress(privateKey.PublicKey)
-	return &Delegate{
-		client:     client,
-		bot:        address,
-		privateKey: privateKey,
-		safe:       common.HexToAddress(safe),
-		argus:      common.HexToAddress(argus),
-	}
-}
 
-func (d *Delegate) WithdrawETHFromGatewayV3(amount *big.Int) error {
-	approvalData, err := buildAtokenApproval(GateWayV3, amount)
+	client, err := ethclient.DialContext(ctx, rpcUrl)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("连接委托人RPC失败: %w", err)
 	}
-	withdrawData, err := buildGatewayV3WithdrawETH(InkBridgeProxy, d.safe, amount)
-	if err != nil {
-		return err
+
+	d := &Delegate{
+		client: client,
+		bot:    signer.Address(),
+		signer: signer,
+		safe:   common.HexToAddress(safe),
+		argus:  common.HexToAddress(argus),
+		logger: logger,
 	}
 
-	var addrs []common.Address
-	var values []*big.Int
-	var datas [][]byte
-	addrs = append(addrs, AInkWlWETH, GateWayV3)
-	values = append(values, big.NewInt(0), big.NewInt(0))
-	datas = append(datas, approvalData, withdrawData)
+	if err := d.verify(ctx, chainID); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return d, nil
+}
 
-	safeExecData, err := buildSafeExecTransactions(addrs, values, datas)
-	if err != nil {
-		return err
+// verify 校验 chain ID 和委托人授权
+func (d *Delegate) verify(ctx context.Context, chainID uint64) error {
+	if chainID != 0 {
+		actual, err := d.client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("获取 chain ID 失败: %w", err)
+		}
+		if actual.Uint64() != chainID {
+			return fmt.Errorf("chain ID 不匹配: 期望 %d, 实际 %s", chainID, actual)
+		}
 	}
 
-	txHash, err := d.SendTransaction(d.bot, d.argus, big.NewInt(0), safeExecData)
+	delegates, err := d.Delegates(ctx)
 	if err != nil {
-		return err
+		return fmt.Errorf("查询 Argus 委托人失败: %w", err)
 	}
-
-	log.Printf("Transaction sent: %s", txHash.Hex())
-	return nil
+	for _, delegate := range delegates {
+		if delegate == d.bot {
+			return nil
+		}
+	}
+	return fmt.Errorf("bot 地址 %s 不是 Argus %s 的授权委托人", d.bot.Hex(), d.argus.Hex())
 }
 
-func (d *Delegate) SendTransaction(from, to common.Address, value *big.Int, data []byte) (common.Hash, error) {
-	// Get nonce
-	nonce, err := d.client.PendingNonceAt(context.Background(), d.bot)
+// Delegates 返回 Argus 上的全部授权委托人
+func (d *Delegate) Delegates(ctx context.Context) ([]common.Address, error) {
+	argus, err := abi.JSON(strings.NewReader(safeABI))
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-
-	// Get chain ID
-	chainID, err := d.client.ChainID(context.Background())
+	data, err := argus.Pack("getAllDelegates")
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-
-	// Estimate gas limit
-	gasLimit, err := d.client.EstimateGas(context.Background(), ethereum.CallMsg{
-		From:  from,
-		To:    &to,
-		Value: value,
-		Data:  data,
-	})
+	output, err := d.client.CallContract(ctx, ethereum.CallMsg{To: &d.argus, Data: data}, nil)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	if gasLimit < uint64(3000000) {
-		gasLimit = uint64(3000000)
+	var delegates []common.Address
+	if err := argus.UnpackIntoInterface(&delegates, "getAllDelegates", output); err != nil {
+		return nil, err
 	}
-	fmt.Printf("gas limit: %d\n", gasLimit)
+	return delegates, nil
+}
 
-	// Get gas price suggestions
-	gasTipCap, err := d.client.SuggestGasTipCap(context.Background())
-	if err != nil {
-		return common.Hash{}, err
-	}
+// Bot 返回委托人（bot）地址
+func (d *Delegate) Bot() common.Address {
+	return d.bot
+}
 
-	// Get base fee from latest block
-	header, err := d.client.HeaderByNumber(context.Background(), nil)
-	if err != nil {
-		return common.Hash{}, err
-	}
-	baseFee := header.BaseFee
+// Close 关闭 RPC 连接和签名器
+func (d *Delegate) Close() {
+	d.client.Close()
+	d.signer.Close()
+}
 
-	// Set max fee per gas (base fee * 2 + tip)
-	gasFeeCap := new(big.Int).Add(
-		gasTipCap,
-		new(big.Int).Mul(baseFee, big.NewInt(2)),
-	)
+// Call Safe 通过 Argus 执行的单个调用
+type Call struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+// Safe 返回 Safe 多签地址
+func (d *Delegate) Safe() common.Address {
+	return d.safe
+}
 
-	// Create EIP-1559 transaction
-	tx := types.NewTx(&types.DynamicFeeTx{
-		ChainID:   chainID,
-		Nonce:     nonce,
-		GasTipCap: gasTipCap,
-		GasFeeCap: gasFeeCap,
-		Gas:       gasLimit,
-		To:        &to,
-		Value:     value,
-		Data:      data,
-	})
-
-	// Sign transaction
-	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), d.privateKey)
+func (d *Delegate) WithdrawETHFromGatewayV3(ctx context.Context, amount *big.Int) error {
+	calls, err := BuildGatewayWithdrawETHCalls(amount, d.safe)
 	if err != nil {
-		return common.Hash{}, err
+		return err
 	}
 
-	// Send transaction
-	err = d.client.SendTransaction(context.Background(), signedTx)
+	result, err := d.ExecCallsAndWait(ctx, calls, TxOptions{MaxReplacements: DefaultMaxReplacements})
 	if err != nil {
-		return common.Hash{}, err
+		return err
 	}
 
-	return signedTx.Hash(), nil
+	d.logger.Info("交易已上链",
+		zap.String("tx_hash", result.Hash.Hex()),
+		zap.Uint64("block", result.Receipt.BlockNumber.Uint64()),
+	)
+	return nil
 }
@@ -0,0 +1,124 @@
+package contracts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+)
+
+// NewAccount 根据配置创建监控合约
+func NewAccount(cfg config.ContractConfig) (Account, error) {
+	if !common.IsHexAddress(cfg.Address) {
+		return nil, fmt.Errorf("合约 %s 地址无效: %q", cfg.Name, cfg.Address)
+	}
+	address := common.HexToAddress(cfg.Address)
+
+	// abi_call 的参数按方法输入类型转换，不限于地址
+	if cfg.Type == TypeABICall {
+		return newABICallFromConfig(cfg, address)
+	}
+
+	params, err := addressParams(cfg.MethodParams)
+	if err != nil {
+		return nil, fmt.Errorf("合约 %s 参数无效: %w", cfg.Name, err)
+	}
+
+	switch cfg.Type {
+	case TypePauseSimple:
+		if len(params) != 0 {
+			return nil, fmt.Errorf("合约 %s 类型 %s 不接受 method_params", cfg.Name, cfg.Type)
+		}
+		return NewPauseChecker(cfg.Name, address, cfg.Type, methodOrDefault(cfg.Method, "paused"), nil), nil
+	case TypePauseIdentifier:
+		return NewPauseChecker(cfg.Name, address, cfg.Type, methodOrDefault(cfg.Method, "paused"), params), nil
+	case TypeGetPaused:
+		if len(params) == 0 {
+			params = []common.Address{common.HexToAddress(L2WETH)}
+		}
+		return NewPauseChecker(cfg.Name, address, cfg.Type, methodOrDefault(cfg.Method, "getPaused"), params), nil
+	case TypePriceFeed:
+		if cfg.Method == "latestRoundData" {
+			return NewRoundFeed(cfg.Name, address, cfg.Decimals, time.Duration(cfg.Heartbeat)*time.Second), nil
+		}
+		if cfg.Heartbeat != 0 {
+			return nil, fmt.Errorf("合约 %s 的 heartbeat 只适用于 method: latestRoundData", cfg.Name)
+		}
+		decimals := cfg.Decimals
+		if decimals == 0 {
+			decimals = DefaultPriceFeedDecimals
+		}
+		return NewPriceFeed(cfg.Name, address, methodOrDefault(cfg.Method, "latestAnswer"), decimals), nil
+	case TypeReserveCap:
+		// method_params: [资产地址, AaveProtocolDataProvider地址]，均可省略
+		asset := common.HexToAddress(L2WETH)
+		dataProvider := common.HexToAddress(DefaultL2AaveProtocolDataProvider)
+		if len(params) > 0 {
+			asset = params[0]
+		}
+		if len(params) > 1 {
+			dataProvider = params[1]
+		}
+		return NewReserveCap(cfg.Name, address, dataProvider, asset), nil
+	default:
+		return nil, fmt.Errorf("合约 %s 类型不支持: %q", cfg.Name, cfg.Type)
+	}
+}
+
+// newABICallFromConfig 根据 signature 或 abi 配置创建 ABICall
+func newABICallFromConfig(cfg config.ContractConfig, address common.Address) (Account, error) {
+	var (
+		method abi.Method
+		err    error
+	)
+	switch {
+	case cfg.Signature != "" && cfg.ABI != "":
+		return nil, fmt.Errorf("合约 %s 的 signature 和 abi 只能配置一个", cfg.Name)
+	case cfg.Signature != "":
+		method, err = ParseMethodSignature(cfg.Signature)
+	case cfg.ABI != "":
+		method, err = ParseMethodABI(cfg.ABI, cfg.Method)
+	default:
+		return nil, fmt.Errorf("合约 %s 类型 %s 需要配置 signature 或 abi", cfg.Name, cfg.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("合约 %s 方法定义无效: %w", cfg.Name, err)
+	}
+
+	opts := ABICallOptions{Output: cfg.Output, Decimals: cfg.Decimals}
+	if cfg.Scale != nil {
+		if *cfg.Scale <= 0 {
+			return nil, fmt.Errorf("合约 %s 的 scale 必须大于0: %v", cfg.Name, *cfg.Scale)
+		}
+		opts.Scale = *cfg.Scale
+	}
+	account, err := NewABICall(cfg.Name, address, method, cfg.MethodParams, opts)
+	if err != nil {
+		return nil, fmt.Errorf("合约 %s 配置无效: %w", cfg.Name, err)
+	}
+	return account, nil
+}
+
+// methodOrDefault 返回配置的方法名，未配置时返回默认值
+func methodOrDefault(method, defaultMethod string) string {
+	if method != "" {
+		return method
+	}
+	return defaultMethod
+}
+
+// addressParams 将配置中的方法参数解析为地址列表
+func addressParams(params []interface{}) ([]common.Address, error) {
+	addrs := make([]common.Address, 0, len(params))
+	for i, p := range params {
+		s, ok := p.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("method_params[%d] 不是有效地址: %v", i, p)
+		}
+		addrs = append(addrs, common.HexToAddress(s))
+	}
+	return addrs, nil
+}
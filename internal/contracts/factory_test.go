@@ -0,0 +1,119 @@
+package contracts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+)
+
+// TestNewAccount 测试按合约类型创建监控合约及默认参数
+func TestNewAccount(t *testing.T) {
+	const addr = "0x0000000000000000000000000000000000000001"
+	const param = "0x0000000000000000000000000000000000000002"
+
+	tests := []struct {
+		name  string
+		cfg   config.ContractConfig
+		check func(t *testing.T, account Account)
+	}{
+		{
+			name: "pause_simple 默认 paused()",
+			cfg:  config.ContractConfig{Type: TypePauseSimple},
+			check: func(t *testing.T, account Account) {
+				assert.Equal(t, "paused()", account.(*PauseChecker).Signature())
+			},
+		},
+		{
+			name: "pause_with_identifier 带地址参数",
+			cfg:  config.ContractConfig{Type: TypePauseIdentifier, MethodParams: []interface{}{param}},
+			check: func(t *testing.T, account Account) {
+				assert.Equal(t, "paused(address)", account.(*PauseChecker).Signature())
+			},
+		},
+		{
+			name: "get_paused 默认查询 WETH",
+			cfg:  config.ContractConfig{Type: TypeGetPaused},
+			check: func(t *testing.T, account Account) {
+				p := account.(*PauseChecker)
+				assert.Equal(t, "getPaused(address)", p.Signature())
+				assert.Equal(t, []common.Address{common.HexToAddress(L2WETH)}, p.params)
+			},
+		},
+		{
+			name: "price_feed 默认 latestAnswer 和8位精度",
+			cfg:  config.ContractConfig{Type: TypePriceFeed},
+			check: func(t *testing.T, account Account) {
+				f := account.(*PriceFeed)
+				assert.Equal(t, "latestAnswer", f.method)
+				assert.Equal(t, DefaultPriceFeedDecimals, f.decimals)
+			},
+		},
+		{
+			name: "price_feed latestRoundData",
+			cfg:  config.ContractConfig{Type: TypePriceFeed, Method: "latestRoundData", Heartbeat: 60},
+			check: func(t *testing.T, account Account) {
+				assert.Equal(t, time.Minute, account.(*RoundFeed).heartbeat)
+			},
+		},
+		{
+			name: "reserve_cap 按参数覆盖资产",
+			cfg:  config.ContractConfig{Type: TypeReserveCap, MethodParams: []interface{}{param}},
+			check: func(t *testing.T, account Account) {
+				r := account.(*InkWLWEth)
+				assert.Equal(t, common.HexToAddress(param), r.asset)
+				assert.Equal(t, common.HexToAddress(DefaultL2AaveProtocolDataProvider), r.dataProvider)
+			},
+		},
+		{
+			name: "abi_call",
+			cfg:  config.ContractConfig{Type: TypeABICall, Signature: "totalSupply() returns (uint256)"},
+			check: func(t *testing.T, account Account) {
+				assert.IsType(t, &ABICall{}, account)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Name, tt.cfg.Address = "test", addr
+			account, err := NewAccount(tt.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, "test", account.Name())
+			assert.Equal(t, tt.cfg.Type, account.Type())
+			assert.Equal(t, common.HexToAddress(addr), account.Address())
+			tt.check(t, account)
+		})
+	}
+}
+
+// TestNewAccount_Invalid 测试无效的合约配置
+func TestNewAccount_Invalid(t *testing.T) {
+	const addr = "0x0000000000000000000000000000000000000001"
+	zero, negative := 0.0, -1.0
+	tests := []struct {
+		name string
+		cfg  config.ContractConfig
+	}{
+		{"地址无效", config.ContractConfig{Type: TypePauseSimple, Address: "0x01"}},
+		{"类型不支持", config.ContractConfig{Type: "unknown", Address: addr}},
+		{"参数不是地址", config.ContractConfig{Type: TypePauseIdentifier, Address: addr, MethodParams: []interface{}{1}}},
+		{"pause_simple 不接受参数", config.ContractConfig{Type: TypePauseSimple, Address: addr, MethodParams: []interface{}{addr}}},
+		{"heartbeat 只适用于 latestRoundData", config.ContractConfig{Type: TypePriceFeed, Address: addr, Heartbeat: 60}},
+		{"abi_call 缺少方法定义", config.ContractConfig{Type: TypeABICall, Address: addr}},
+		{"abi_call signature 和 abi 同时配置", config.ContractConfig{Type: TypeABICall, Address: addr, Signature: "totalSupply() returns (uint256)", ABI: "[]"}},
+		{"abi_call decimals 为负数", config.ContractConfig{Type: TypeABICall, Address: addr, Signature: "totalSupply() returns (uint256)", Decimals: -1}},
+		{"abi_call scale 为0", config.ContractConfig{Type: TypeABICall, Address: addr, Signature: "totalSupply() returns (uint256)", Scale: &zero}},
+		{"abi_call scale 为负数", config.ContractConfig{Type: TypeABICall, Address: addr, Signature: "totalSupply() returns (uint256)", Scale: &negative}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Name = "test"
+			_, err := NewAccount(tt.cfg)
+			assert.ErrorContains(t, err, "合约 test")
+		})
+	}
+}
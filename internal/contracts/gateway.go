@@ -39,6 +39,18 @@ func buildGatewayV3WithdrawETH(arg0, to common.Address, amount *big.Int) ([]byte
 	return data, nil
 }
 
+func buildGatewayV3RepayETH(arg0, onBehalfOf common.Address, amount *big.Int) ([]byte, error) {
+	gatewayV3, err := abi.JSON(strings.NewReader(gatewayV3ABI))
+	if err != nil {
+		return nil, err
+	}
+	data, err := gatewayV3.Pack("repayETH", arg0, amount, onBehalfOf)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 func buildAtokenApproval(spender common.Address, amount *big.Int) ([]byte, error) {
 	atoken, err := abi.JSON(strings.NewReader(atokenABI))
 	if err != nil {
@@ -50,3 +62,30 @@ func buildAtokenApproval(spender common.Address, amount *big.Int) ([]byte, error
 	}
 	return data, nil
 }
+
+// BuildGatewayWithdrawETHCalls 构造从 GatewayV3 取出 ETH 的调用：先授权 aToken，再 withdrawETH
+func BuildGatewayWithdrawETHCalls(amount *big.Int, to common.Address) ([]Call, error) {
+	approvalData, err := buildAtokenApproval(GateWayV3, amount)
+	if err != nil {
+		return nil, err
+	}
+	withdrawData, err := buildGatewayV3WithdrawETH(InkBridgeProxy, to, amount)
+	if err != nil {
+		return nil, err
+	}
+	return []Call{
+		{To: AInkWlWETH, Value: big.NewInt(0), Data: approvalData},
+		{To: GateWayV3, Value: big.NewInt(0), Data: withdrawData},
+	}, nil
+}
+
+// BuildGatewayRepayETHCalls 构造通过 GatewayV3 用 ETH 偿还债务的调用
+func BuildGatewayRepayETHCalls(amount *big.Int, onBehalfOf common.Address) ([]Call, error) {
+	repayData, err := buildGatewayV3RepayETH(InkBridgeProxy, onBehalfOf, amount)
+	if err != nil {
+		return nil, err
+	}
+	return []Call{
+		{To: GateWayV3, Value: new(big.Int).Set(amount), Data: repayData},
+	}, nil
+}
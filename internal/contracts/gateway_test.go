@@ -1,11 +1,14 @@
 package contracts
 
 import (
+	"context"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 var (
@@ -22,7 +25,10 @@ var (
 // 取ETH
 func TestWithdrawETH(t *testing.T) {
 	amount, _ := new(big.Int).SetString("100000000000000027464", 10)
-	delegate := NewDelegate(localRPC, privateKey, safe, l2Argus)
-	err := delegate.WithdrawETHFromGatewayV3(amount)
+	signer, err := NewPrivateKeySigner(privateKey)
+	require.NoError(t, err)
+	delegate, err := NewDelegate(context.Background(), localRPC, signer, safe, l2Argus, 0, zap.NewNop())
+	require.NoError(t, err)
+	err = delegate.WithdrawETHFromGatewayV3(context.Background(), amount)
 	assert.NoError(t, err)
 }
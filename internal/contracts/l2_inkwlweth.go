@@ -15,18 +15,32 @@ import (
 
 type InkWLWEth struct {
 	BaseContract
+	dataProvider common.Address
+	asset        common.Address
 }
 
 func NewInkWLWEth(address common.Address) *InkWLWEth {
+	return NewReserveCap(
+		"variable_debt_InkWlWETH",
+		address,
+		common.HexToAddress(DefaultL2AaveProtocolDataProvider),
+		common.HexToAddress(L2WETH),
+	)
+}
+
+// NewReserveCap 创建剩余供应容量监控，address 为用于读取 totalSupply 的代币地址
+func NewReserveCap(name string, address, dataProvider, asset common.Address) *InkWLWEth {
 	return &InkWLWEth{
-		BaseContract: NewBaseContract("variable_debt_InkWlWETH", address, TypeReserveCap),
+		BaseContract: NewBaseContract(name, address, TypeReserveCap),
+		dataProvider: dataProvider,
+		asset:        asset,
 	}
 }
 
 func (p *InkWLWEth) Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error) {
 	// 1. 调用 AAveProtocolDataProvider.getReserveCaps(address) 获取 supplyCap
-	aaveAddr := DefaultL2AaveProtocolDataProvider
-	assetAddr := "0x4200000000000000000000000000000000000006"
+	aaveAddr := p.dataProvider.Hex()
+	assetAddr := p.asset.Hex()
 
 	// 构造 getReserveCaps(address) 调用
 	methodID := crypto.Keccak256([]byte("getReserveCaps(address)"))[:4]
@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+)
+
+// PauseChecker 通用暂停状态检查，方法名和地址参数来自配置
+type PauseChecker struct {
+	BaseContract
+	method string
+	params []common.Address
+}
+
+// NewPauseChecker 创建 PauseChecker 实例
+// 方法签名由方法名和参数个数拼出，例如 paused() 或 getPaused(address)
+func NewPauseChecker(name string, address common.Address, typeName, method string, params []common.Address) *PauseChecker {
+	return &PauseChecker{
+		BaseContract: NewBaseContract(name, address, typeName),
+		method:       method,
+		params:       params,
+	}
+}
+
+// Signature 返回方法签名
+func (p *PauseChecker) Signature() string {
+	types := make([]string, len(p.params))
+	for i := range p.params {
+		types[i] = "address"
+	}
+	return p.method + "(" + strings.Join(types, ",") + ")"
+}
+
+// Monitor 监控合约的暂停状态
+func (p *PauseChecker) Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error) {
+	data := crypto.Keccak256([]byte(p.Signature()))[:4]
+	for _, param := range p.params {
+		data = append(data, common.LeftPadBytes(param.Bytes(), 32)...)
+	}
+
+	paused, err := caller.CallBool(ctx, p.address.Hex(), data)
+	if err != nil {
+		return 0, err
+	}
+
+	// 返回指标值: true=1.0, false=0.0
+	if paused {
+		return 1.0, nil
+	}
+	return 0.0, nil
+}
@@ -0,0 +1,46 @@
+package contracts
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+)
+
+// DefaultPriceFeedDecimals Chainlink 类价格源默认精度
+const DefaultPriceFeedDecimals = 8
+
+// PriceFeed 通用价格源，调用无参方法（默认 latestAnswer()）读取价格
+type PriceFeed struct {
+	BaseContract
+	method   string
+	decimals int
+}
+
+// NewPriceFeed 创建 PriceFeed 实例
+func NewPriceFeed(name string, address common.Address, method string, decimals int) *PriceFeed {
+	return &PriceFeed{
+		BaseContract: NewBaseContract(name, address, TypePriceFeed),
+		method:       method,
+		decimals:     decimals,
+	}
+}
+
+// Monitor 读取价格并按精度换算
+func (p *PriceFeed) Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error) {
+	methodID := crypto.Keccak256([]byte(p.method + "()"))[:4]
+	price, err := caller.CallInt256(ctx, p.address.Hex(), methodID)
+	if err != nil {
+		return 0, err
+	}
+
+	priceFloat := new(big.Float).SetInt(price)
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p.decimals)), nil))
+	priceFloat.Quo(priceFloat, divisor)
+	value, _ := priceFloat.Float64()
+
+	return value, nil
+}
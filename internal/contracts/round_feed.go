@@ -0,0 +1,155 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+)
+
+// DefaultFeedHeartbeat 价格源默认心跳间隔（秒），超过该时间未更新视为过期
+const DefaultFeedHeartbeat = 3600
+
+// 价格源附加指标的名称后缀
+const (
+	DetailRoundID         = "round_id"            // 轮次ID（低64位，即聚合器内的轮次）
+	DetailUpdatedAge      = "updated_age_seconds" // 最新价格更新时间距今的秒数
+	DetailRoundStale      = "round_stale"         // 价格超过心跳间隔未更新为1
+	DetailRoundIncomplete = "round_incomplete"    // answeredInRound < roundId 为1
+	DetailAnswerInvalid   = "answer_invalid"      // 价格不大于0为1
+)
+
+var (
+	latestRoundDataID = crypto.Keccak256([]byte("latestRoundData()"))[:4]
+	decimalsID        = crypto.Keccak256([]byte("decimals()"))[:4]
+	aggregatorRound   = new(big.Int).SetUint64(^uint64(0)) // roundId 的低64位为聚合器内的轮次，高16位为 phaseId
+)
+
+// Round latestRoundData() 返回的轮次数据
+type Round struct {
+	RoundID         *big.Int
+	Answer          *big.Int
+	StartedAt       uint64
+	UpdatedAt       uint64
+	AnsweredInRound *big.Int
+}
+
+// RoundFeed Chainlink AggregatorV3 接口的价格源，通过 latestRoundData() 读取价格，
+// 精度未配置时读取 decimals()；除价格外还检查价格是否过期、轮次是否完成以及价格是否为正数
+type RoundFeed struct {
+	BaseContract
+	decimals  int // 0 表示读取 decimals()
+	heartbeat time.Duration
+}
+
+// NewRoundFeed 创建 RoundFeed 实例，decimals 为 0 时读取合约的 decimals()，heartbeat 为 0 时使用默认值
+func NewRoundFeed(name string, address common.Address, decimals int, heartbeat time.Duration) *RoundFeed {
+	if heartbeat <= 0 {
+		heartbeat = DefaultFeedHeartbeat * time.Second
+	}
+	return &RoundFeed{
+		BaseContract: NewBaseContract(name, address, TypePriceFeed),
+		decimals:     decimals,
+		heartbeat:    heartbeat,
+	}
+}
+
+// DetailNames 返回价格源导出的附加指标名称后缀
+func (f *RoundFeed) DetailNames() []string {
+	return []string{DetailRoundID, DetailUpdatedAge, DetailRoundStale, DetailRoundIncomplete, DetailAnswerInvalid}
+}
+
+// IsClockDetail 判断附加指标是否由本地时钟计算，多个节点在同一区块读取时这类指标也会略有差别
+func IsClockDetail(name string) bool {
+	return name == DetailUpdatedAge
+}
+
+// Monitor 读取最新价格并按精度换算
+func (f *RoundFeed) Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error) {
+	reading, err := f.Read(ctx, caller)
+	if err != nil {
+		return 0, err
+	}
+	return reading.Value, nil
+}
+
+// Read 读取最新轮次，返回价格以及轮次ID、更新时间等附加指标
+func (f *RoundFeed) Read(ctx context.Context, caller *client.ContractCaller) (*Reading, error) {
+	// 两个调用互不依赖，批量读取时在同一轮完成
+	round, roundErr := f.LatestRound(ctx, caller)
+	decimals, decimalsErr := f.Decimals(ctx, caller)
+	if roundErr != nil {
+		return nil, roundErr
+	}
+	if decimalsErr != nil {
+		return nil, decimalsErr
+	}
+
+	price := new(big.Float).SetInt(round.Answer)
+	price.Quo(price, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	value, _ := price.Float64()
+
+	age := max(time.Since(time.Unix(int64(round.UpdatedAt), 0)), 0)
+	reading := &Reading{
+		Value: value,
+		Details: map[string]float64{
+			DetailRoundID:         float64(new(big.Int).And(round.RoundID, aggregatorRound).Uint64()),
+			DetailUpdatedAge:      age.Seconds(),
+			DetailRoundStale:      0,
+			DetailRoundIncomplete: 0,
+			DetailAnswerInvalid:   0,
+		},
+	}
+	if age > f.heartbeat {
+		reading.Details[DetailRoundStale] = 1
+		reading.Warnings = append(reading.Warnings, fmt.Sprintf("价格已 %s 未更新，超过心跳间隔 %s", age.Truncate(time.Second), f.heartbeat))
+	}
+	if round.AnsweredInRound.Cmp(round.RoundID) < 0 {
+		reading.Details[DetailRoundIncomplete] = 1
+		reading.Warnings = append(reading.Warnings, fmt.Sprintf("轮次未完成: answeredInRound %s < roundId %s", round.AnsweredInRound, round.RoundID))
+	}
+	if round.Answer.Sign() <= 0 {
+		reading.Details[DetailAnswerInvalid] = 1
+		reading.Warnings = append(reading.Warnings, fmt.Sprintf("价格不是正数: %s", round.Answer))
+	}
+	return reading, nil
+}
+
+// LatestRound 调用 latestRoundData() 读取最新轮次
+func (f *RoundFeed) LatestRound(ctx context.Context, caller *client.ContractCaller) (*Round, error) {
+	result, err := caller.CallRaw(ctx, f.address.Hex(), latestRoundDataID)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) < 5*32 {
+		return nil, fmt.Errorf("latestRoundData 返回数据长度不足: %d", len(result))
+	}
+	word := func(i int) *big.Int { return new(big.Int).SetBytes(result[i*32 : (i+1)*32]) }
+	return &Round{
+		RoundID:         word(0),
+		Answer:          client.ToInt256(word(1)),
+		StartedAt:       word(2).Uint64(),
+		UpdatedAt:       word(3).Uint64(),
+		AnsweredInRound: word(4),
+	}, nil
+}
+
+// Decimals 返回价格精度，未配置时调用 decimals() 读取
+func (f *RoundFeed) Decimals(ctx context.Context, caller *client.ContractCaller) (int, error) {
+	if f.decimals > 0 {
+		return f.decimals, nil
+	}
+	decimals, err := caller.CallUint256(ctx, f.address.Hex(), decimalsID)
+	if err != nil {
+		return 0, fmt.Errorf("读取 decimals 失败: %w", err)
+	}
+	if !decimals.IsUint64() || decimals.Uint64() > 36 {
+		return 0, fmt.Errorf("decimals 无效: %s", decimals)
+	}
+	return int(decimals.Uint64()), nil
+}
@@ -0,0 +1,81 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+)
+
+// aggregatorNode 模拟 AggregatorV3 价格源
+type aggregatorNode struct {
+	round    [5]*big.Int
+	decimals int64
+}
+
+func (n *aggregatorNode) Call(args struct {
+	Input hexutil.Bytes `json:"input"`
+}, block interface{}) hexutil.Bytes {
+	if bytes.Equal(args.Input, decimalsID) {
+		return common.BigToHash(big.NewInt(n.decimals)).Bytes()
+	}
+	var out []byte
+	for _, v := range n.round {
+		out = append(out, math.U256Bytes(new(big.Int).Set(v))...)
+	}
+	return out
+}
+
+func newAggregatorCaller(t *testing.T, node *aggregatorNode) *client.ContractCaller {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	caller, err := client.NewContractCaller(httpServer.URL, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(caller.Close)
+	return caller
+}
+
+// TestRoundFeed 测试 latestRoundData 价格源的价格换算和轮次检查
+func TestRoundFeed(t *testing.T) {
+	phase := new(big.Int).Lsh(big.NewInt(2), 64) // phaseId 2
+	updatedAt := big.NewInt(time.Now().Add(-10 * time.Minute).Unix())
+	node := &aggregatorNode{
+		round:    [5]*big.Int{new(big.Int).Add(phase, big.NewInt(7)), big.NewInt(250000000000), updatedAt, updatedAt, new(big.Int).Add(phase, big.NewInt(7))},
+		decimals: 8,
+	}
+	caller := newAggregatorCaller(t, node)
+	feed := NewRoundFeed("eth_usd", common.Address{}, 0, time.Hour)
+
+	reading, err := feed.Read(context.Background(), caller)
+	require.NoError(t, err)
+	assert.Equal(t, 2500.0, reading.Value)
+	assert.Equal(t, 7.0, reading.Details[DetailRoundID])
+	assert.InDelta(t, 600, reading.Details[DetailUpdatedAge], 5)
+	assert.Empty(t, reading.Warnings)
+
+	// 过期、轮次未完成、价格为负数
+	node.round[1] = big.NewInt(-1)
+	node.round[4] = new(big.Int).Add(phase, big.NewInt(6))
+	feed = NewRoundFeed("eth_usd", common.Address{}, 0, 5*time.Minute)
+	reading, err = feed.Read(context.Background(), caller)
+	require.NoError(t, err)
+	assert.Equal(t, -1e-8, reading.Value)
+	assert.Equal(t, 1.0, reading.Details[DetailRoundStale])
+	assert.Equal(t, 1.0, reading.Details[DetailRoundIncomplete])
+	assert.Equal(t, 1.0, reading.Details[DetailAnswerInvalid])
+	assert.Len(t, reading.Warnings, 3)
+}
@@ -0,0 +1,245 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+)
+
+// 签名器类型
+const (
+	SignerPrivateKey = "private_key" // 配置中的明文私钥（默认，仅用于测试环境）
+	SignerKeystore   = "keystore"    // 加密的 go-ethereum keystore 文件
+	SignerRemote     = "remote"      // 远程签名服务（eth_signTransaction，兼容 Clef）
+)
+
+// DefaultRemoteSignMethod 远程签名默认使用的 JSON-RPC 方法，Clef 使用 account_signTransaction
+const DefaultRemoteSignMethod = "eth_signTransaction"
+
+// Signer 交易签名器，私钥不需要出现在配置中
+type Signer interface {
+	// Address 返回签名地址
+	Address() common.Address
+	// SignTx 对交易签名
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// Close 释放签名器持有的连接
+	Close()
+}
+
+// NewSigner 根据配置创建签名器，未配置类型时使用 emergency.private_key
+func NewSigner(ctx context.Context, cfg config.SignerConfig, privateKey string) (Signer, error) {
+	switch cfg.Type {
+	case "", SignerPrivateKey:
+		return NewPrivateKeySigner(privateKey)
+	case SignerKeystore:
+		passphrase, err := readPassphrase(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewKeystoreSigner(cfg.KeystoreFile, passphrase)
+	case SignerRemote:
+		if !common.IsHexAddress(cfg.Address) {
+			return nil, fmt.Errorf("远程签名器的 address 不是有效地址: %q", cfg.Address)
+		}
+		return NewRemoteSigner(ctx, cfg.URL, common.HexToAddress(cfg.Address), cfg.Method)
+	default:
+		return nil, fmt.Errorf("不支持的签名器类型: %q", cfg.Type)
+	}
+}
+
+// readPassphrase 从文件或环境变量读取 keystore 密码
+func readPassphrase(cfg config.SignerConfig) (string, error) {
+	if cfg.PassphraseFile != "" {
+		data, err := os.ReadFile(cfg.PassphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("读取 keystore 密码文件失败: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	if cfg.PassphraseEnv != "" {
+		passphrase, ok := os.LookupEnv(cfg.PassphraseEnv)
+		if !ok {
+			return "", fmt.Errorf("环境变量 %s 未设置", cfg.PassphraseEnv)
+		}
+		return passphrase, nil
+	}
+	return "", fmt.Errorf("keystore 签名器需要配置 passphrase_file 或 passphrase_env")
+}
+
+// privateKeySigner 使用内存中的私钥签名
+type privateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner 使用十六进制私钥创建签名器
+func NewPrivateKeySigner(hexKey string) (Signer, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("解析私钥失败: %w", err)
+	}
+	return newKeySigner(key), nil
+}
+
+// newKeySigner 使用私钥创建签名器
+func newKeySigner(key *ecdsa.PrivateKey) *privateKeySigner {
+	return &privateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (s *privateKeySigner) Address() common.Address { return s.address }
+
+// Close 本地签名器没有需要释放的资源
+func (s *privateKeySigner) Close() {}
+
+func (s *privateKeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// NewKeystoreSigner 解密 keystore 文件创建签名器
+func NewKeystoreSigner(path, passphrase string) (Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 keystore 文件失败: %w", err)
+	}
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("解密 keystore 文件失败: %w", err)
+	}
+	return newKeySigner(key.PrivateKey), nil
+}
+
+// remoteSigner 通过 JSON-RPC 远程签名，私钥不进入本进程
+type remoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+	method  string
+}
+
+// signTxArgs eth_signTransaction / account_signTransaction 的参数
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// NewRemoteSigner 连接远程签名服务，method 为空时使用 eth_signTransaction
+func NewRemoteSigner(ctx context.Context, url string, address common.Address, method string) (Signer, error) {
+	if url == "" {
+		return nil, fmt.Errorf("远程签名器的 url 不能为空")
+	}
+	if method == "" {
+		method = DefaultRemoteSignMethod
+	}
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("连接远程签名服务失败: %w", err)
+	}
+	return &remoteSigner{client: client, address: address, method: method}, nil
+}
+
+func (s *remoteSigner) Address() common.Address { return s.address }
+
+// SignTx 请求远程签名，并校验返回的交易与请求一致
+func (s *remoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:                 s.address,
+		To:                   tx.To(),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Data:                 tx.Data(),
+		ChainID:              (*hexutil.Big)(chainID),
+	}
+
+	var raw json.RawMessage
+	if err := s.client.CallContext(ctx, &raw, s.method, args); err != nil {
+		return nil, fmt.Errorf("远程签名失败: %w", err)
+	}
+	encoded, err := decodeSignResult(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(encoded); err != nil {
+		return nil, fmt.Errorf("解析远程签名交易失败: %w", err)
+	}
+	if field := mismatchedField(tx, signed, chainID); field != "" {
+		return nil, fmt.Errorf("远程签名返回的交易与请求不一致: %s", field)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("校验远程签名失败: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("远程签名地址不匹配: 期望 %s, 实际 %s", s.address.Hex(), sender.Hex())
+	}
+	return signed, nil
+}
+
+// Close 关闭与远程签名服务的连接
+func (s *remoteSigner) Close() {
+	s.client.Close()
+}
+
+// mismatchedField 比较签名后的交易与请求，返回第一个不一致的字段，一致时返回空字符串
+func mismatchedField(tx, signed *types.Transaction, chainID *big.Int) string {
+	switch {
+	case signed.Type() != tx.Type():
+		return "type"
+	case signed.ChainId().Cmp(chainID) != 0:
+		return "chainId"
+	case signed.Nonce() != tx.Nonce():
+		return "nonce"
+	case signed.To() == nil || tx.To() == nil || *signed.To() != *tx.To():
+		return "to"
+	case signed.Value().Cmp(tx.Value()) != 0:
+		return "value"
+	case !bytes.Equal(signed.Data(), tx.Data()):
+		return "data"
+	case signed.Gas() != tx.Gas():
+		return "gas"
+	case signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0:
+		return "maxFeePerGas"
+	case signed.GasTipCap().Cmp(tx.GasTipCap()) != 0:
+		return "maxPriorityFeePerGas"
+	}
+	return ""
+}
+
+// decodeSignResult 解析签名结果，兼容 {"raw": "0x..", "tx": {..}} 和直接返回编码交易两种格式
+func decodeSignResult(raw json.RawMessage) ([]byte, error) {
+	var encoded hexutil.Bytes
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		return encoded, nil
+	}
+	var result struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil || len(result.Raw) == 0 {
+		return nil, fmt.Errorf("无法解析远程签名结果: %s", string(raw))
+	}
+	return result.Raw, nil
+}
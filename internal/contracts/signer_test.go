@@ -0,0 +1,192 @@
+package contracts
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+)
+
+// fakeRemoteSigner 本地模拟的远程签名服务
+type fakeRemoteSigner struct {
+	signer *privateKeySigner
+	tamper func(tx *types.DynamicFeeTx) // 签名前篡改交易，模拟被攻破或有缺陷的签名服务
+}
+
+// signTx 按请求参数构造并签名交易
+func (f *fakeRemoteSigner) signTx(args signTxArgs) (hexutil.Bytes, error) {
+	inner := &types.DynamicFeeTx{
+		ChainID:   args.ChainID.ToInt(),
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Data,
+	}
+	if f.tamper != nil {
+		f.tamper(inner)
+	}
+	signed, err := f.signer.SignTx(context.Background(), types.NewTx(inner), inner.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+// ethService eth_signTransaction，直接返回编码交易
+type ethService struct{ *fakeRemoteSigner }
+
+func (s *ethService) SignTransaction(args signTxArgs) (hexutil.Bytes, error) {
+	return s.signTx(args)
+}
+
+// accountService Clef 的 account_signTransaction，返回 {raw, tx}
+type accountService struct{ *fakeRemoteSigner }
+
+func (s *accountService) SignTransaction(args signTxArgs) (map[string]interface{}, error) {
+	raw, err := s.signTx(args)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": raw, "tx": map[string]string{}}, nil
+}
+
+// newFakeSignerServer 启动模拟的远程签名 JSON-RPC 服务
+func newFakeSignerServer(t *testing.T) (*httptest.Server, common.Address) {
+	server, fake := newTamperingSignerServer(t)
+	return server, fake.signer.Address()
+}
+
+// newTamperingSignerServer 启动模拟的远程签名服务，返回的 fakeRemoteSigner 可以设置篡改函数
+func newTamperingSignerServer(t *testing.T) (*httptest.Server, *fakeRemoteSigner) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	fake := &fakeRemoteSigner{signer: newKeySigner(key)}
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &ethService{fake}))
+	require.NoError(t, server.RegisterName("account", &accountService{fake}))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return httpServer, fake
+}
+
+// testTx 测试用的未签名交易
+func testTx() *types.Transaction {
+	to := common.HexToAddress(l2Argus)
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(InkChainID),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1e6),
+		GasFeeCap: big.NewInt(2e9),
+		Gas:       3000000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0xde, 0xad, 0xbe, 0xef},
+	})
+}
+
+// TestRemoteSigner 测试 eth_signTransaction 和 Clef account_signTransaction
+func TestRemoteSigner(t *testing.T) {
+	server, address := newFakeSignerServer(t)
+	chainID := big.NewInt(InkChainID)
+
+	for _, method := range []string{"", "account_signTransaction"} {
+		signer, err := NewRemoteSigner(context.Background(), server.URL, address, method)
+		require.NoError(t, err)
+		assert.Equal(t, address, signer.Address())
+
+		signed, err := signer.SignTx(context.Background(), testTx(), chainID)
+		require.NoError(t, err, method)
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		require.NoError(t, err)
+		assert.Equal(t, address, sender)
+		assert.Equal(t, uint64(7), signed.Nonce())
+	}
+
+	// 签名服务使用的密钥与配置的地址不一致
+	signer, err := NewRemoteSigner(context.Background(), server.URL, common.HexToAddress(bot), "")
+	require.NoError(t, err)
+	_, err = signer.SignTx(context.Background(), testTx(), chainID)
+	assert.ErrorContains(t, err, "地址不匹配")
+	signer.Close()
+}
+
+// TestRemoteSigner_Tampered 测试签名服务返回的交易与请求不一致时拒绝
+func TestRemoteSigner_Tampered(t *testing.T) {
+	server, fake := newTamperingSignerServer(t)
+	signer, err := NewRemoteSigner(context.Background(), server.URL, fake.signer.Address(), "")
+	require.NoError(t, err)
+	t.Cleanup(signer.Close)
+
+	attacker := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	tests := map[string]func(tx *types.DynamicFeeTx){
+		"chainId":              func(tx *types.DynamicFeeTx) { tx.ChainID = big.NewInt(1) },
+		"nonce":                func(tx *types.DynamicFeeTx) { tx.Nonce++ },
+		"to":                   func(tx *types.DynamicFeeTx) { tx.To = &attacker },
+		"value":                func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(1) },
+		"data":                 func(tx *types.DynamicFeeTx) { tx.Data = []byte{0x01} },
+		"gas":                  func(tx *types.DynamicFeeTx) { tx.Gas++ },
+		"maxFeePerGas":         func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(1e12) },
+		"maxPriorityFeePerGas": func(tx *types.DynamicFeeTx) { tx.GasTipCap = big.NewInt(1e12) },
+	}
+	for field, tamper := range tests {
+		fake.tamper = tamper
+		_, err := signer.SignTx(context.Background(), testTx(), big.NewInt(InkChainID))
+		assert.ErrorContains(t, err, "与请求不一致: "+field, field)
+	}
+}
+
+// TestKeystoreSigner 测试从 keystore 文件和密码文件/环境变量创建签名器
+func TestKeystoreSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(filepath.Join(dir, "keystore"), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "secret")
+	require.NoError(t, err)
+	keyFile := account.URL.Path
+
+	passFile := filepath.Join(dir, "pass")
+	require.NoError(t, os.WriteFile(passFile, []byte("secret\n"), 0600))
+
+	signer, err := NewSigner(context.Background(), config.SignerConfig{
+		Type:           SignerKeystore,
+		KeystoreFile:   keyFile,
+		PassphraseFile: passFile,
+	}, "")
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.Address())
+
+	signed, err := signer.SignTx(context.Background(), testTx(), big.NewInt(InkChainID))
+	require.NoError(t, err)
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(InkChainID)), signed)
+	require.NoError(t, err)
+	assert.Equal(t, signer.Address(), sender)
+
+	t.Setenv("TEST_KEYSTORE_PASSPHRASE", "wrong")
+	_, err = NewSigner(context.Background(), config.SignerConfig{
+		Type:          SignerKeystore,
+		KeystoreFile:  keyFile,
+		PassphraseEnv: "TEST_KEYSTORE_PASSPHRASE",
+	}, "")
+	assert.Error(t, err)
+
+	_, err = NewSigner(context.Background(), config.SignerConfig{Type: SignerKeystore, KeystoreFile: keyFile}, "")
+	assert.Error(t, err, "未配置密码来源")
+}
@@ -0,0 +1,247 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DecodedCall 解码后的 Safe 内部调用
+type DecodedCall struct {
+	To     common.Address `json:"to"`
+	Value  *big.Int       `json:"value"`
+	Method string         `json:"method"` // 无法识别时为方法选择器
+	Args   []string       `json:"args"`   // 按 "name=value" 格式输出
+}
+
+// String 返回可读的调用描述
+func (c DecodedCall) String() string {
+	return fmt.Sprintf("%s.%s(%s) value=%s", c.To.Hex(), c.Method, strings.Join(c.Args, ", "), c.Value)
+}
+
+// SimulationResult 应急交易的模拟结果
+type SimulationResult struct {
+	From        common.Address `json:"from"`
+	To          common.Address `json:"to"`
+	Calls       []DecodedCall  `json:"calls"`
+	Success     bool           `json:"success"`
+	GasEstimate uint64         `json:"gas_estimate"`
+	ReturnData  string         `json:"return_data,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+var (
+	knownABIsOnce sync.Once
+	knownABIs     []abi.ABI
+	knownABIsErr  error
+)
+
+// loadKnownABIs 加载用于解码内部调用的 ABI
+func loadKnownABIs() ([]abi.ABI, error) {
+	knownABIsOnce.Do(func() {
+		for _, raw := range []string{atokenABI, gatewayV3ABI, wethABI, l2StandardBridgeABI, safeABI} {
+			parsed, err := abi.JSON(strings.NewReader(raw))
+			if err != nil {
+				knownABIsErr = err
+				return
+			}
+			knownABIs = append(knownABIs, parsed)
+		}
+	})
+	return knownABIs, knownABIsErr
+}
+
+// DecodeSafeExecTransactions 从 execTransactions 调用数据中解出内部调用
+func DecodeSafeExecTransactions(data []byte) ([]Call, error) {
+	safe, err := abi.JSON(strings.NewReader(safeABI))
+	if err != nil {
+		return nil, err
+	}
+	method, err := safe.MethodById(data)
+	if err != nil || method.Name != "execTransactions" {
+		return nil, fmt.Errorf("不是 execTransactions 调用数据")
+	}
+
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("解码 execTransactions 失败: %w", err)
+	}
+	callDatas := *abi.ConvertType(args[0], new([]SafeCallData)).(*[]SafeCallData)
+
+	calls := make([]Call, len(callDatas))
+	for i, cd := range callDatas {
+		calls[i] = Call{To: cd.To, Value: cd.Value, Data: cd.Data}
+	}
+	return calls, nil
+}
+
+// DecodeCall 使用已知 ABI 解码单个调用
+func DecodeCall(call Call) DecodedCall {
+	decoded := DecodedCall{To: call.To, Value: call.Value}
+	if len(call.Data) < 4 {
+		decoded.Method = "fallback"
+		return decoded
+	}
+	decoded.Method = hexutil.Encode(call.Data[:4])
+
+	abis, err := loadKnownABIs()
+	if err != nil {
+		return decoded
+	}
+	for _, parsed := range abis {
+		method, err := parsed.MethodById(call.Data)
+		if err != nil {
+			continue
+		}
+		args, err := decodeArgs(method, call.Data[4:])
+		if err != nil {
+			continue
+		}
+		decoded.Method = method.Name
+		decoded.Args = args
+		return decoded
+	}
+	return decoded
+}
+
+// decodeArgs 按位置解码方法参数，格式为 name=value，未命名的参数使用 arg<i>
+func decodeArgs(method *abi.Method, data []byte) ([]string, error) {
+	values, err := method.Inputs.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, len(values))
+	for i, input := range method.Inputs {
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		args = append(args, fmt.Sprintf("%s=%s", name, formatArg(values[i])))
+	}
+	return args, nil
+}
+
+// formatArg 格式化解码后的参数
+func formatArg(v interface{}) string {
+	switch a := v.(type) {
+	case common.Address:
+		return a.Hex()
+	case []byte:
+		return hexutil.Encode(a)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(a))
+		for k := range a {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + "=" + formatArg(a[k])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Simulate 构造完整的 execTransactions 交易，以 bot 地址对 Argus 执行 eth_call 和 EstimateGas，不广播
+func (d *Delegate) Simulate(ctx context.Context, calls []Call) (*SimulationResult, error) {
+	var addrs []common.Address
+	var values []*big.Int
+	var datas [][]byte
+	for _, call := range calls {
+		addrs = append(addrs, call.To)
+		values = append(values, call.Value)
+		datas = append(datas, call.Data)
+	}
+
+	safeExecData, err := buildSafeExecTransactions(addrs, values, datas)
+	if err != nil {
+		return nil, err
+	}
+
+	// 从实际发送的调用数据解码，确保展示的内容与交易一致
+	innerCalls, err := DecodeSafeExecTransactions(safeExecData)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SimulationResult{From: d.bot, To: d.argus}
+	for _, call := range innerCalls {
+		result.Calls = append(result.Calls, DecodeCall(call))
+	}
+
+	msg := ethereum.CallMsg{
+		From:  d.bot,
+		To:    &d.argus,
+		Value: big.NewInt(0),
+		Data:  safeExecData,
+	}
+
+	returnData, err := d.client.CallContract(ctx, msg, nil)
+	if err != nil {
+		result.Error = DecodeRevert(err)
+		return result, nil
+	}
+	result.ReturnData = hexutil.Encode(returnData)
+
+	gas, err := d.client.EstimateGas(ctx, msg)
+	if err != nil {
+		result.Error = DecodeRevert(err)
+		return result, nil
+	}
+	result.GasEstimate = gas
+	result.Success = true
+
+	return result, nil
+}
+
+// DecodeRevert 从 RPC 错误中解析 revert 原因
+// 支持 Error(string)、Panic(uint256) 和已知 ABI 中的自定义错误，无法识别时返回原始数据
+func DecodeRevert(err error) string {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err.Error()
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error()
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil || len(data) < 4 {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s: %s", err.Error(), DecodeRevertData(data))
+}
+
+// DecodeRevertData 解析 revert 返回数据
+func DecodeRevertData(data []byte) string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if len(data) >= 4 {
+		if abis, err := loadKnownABIs(); err == nil {
+			for _, parsed := range abis {
+				customErr, err := parsed.ErrorByID([4]byte(data[:4]))
+				if err != nil {
+					continue
+				}
+				args, err := customErr.Inputs.Unpack(data[4:])
+				if err != nil {
+					return customErr.Name
+				}
+				return fmt.Sprintf("%s%v", customErr.Name, args)
+			}
+		}
+	}
+	return hexutil.Encode(data)
+}
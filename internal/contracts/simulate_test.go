@@ -0,0 +1,95 @@
+package contracts
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeSafeExecTransactions 测试 execTransactions 调用数据的解码
+func TestDecodeSafeExecTransactions(t *testing.T) {
+	to := common.HexToAddress(safe)
+	amount := big.NewInt(1e18)
+	calls, err := BuildGatewayWithdrawETHCalls(amount, to)
+	require.NoError(t, err)
+
+	var addrs []common.Address
+	var values []*big.Int
+	var datas [][]byte
+	for _, call := range calls {
+		addrs = append(addrs, call.To)
+		values = append(values, call.Value)
+		datas = append(datas, call.Data)
+	}
+	payload, err := buildSafeExecTransactions(addrs, values, datas)
+	require.NoError(t, err)
+
+	decoded, err := DecodeSafeExecTransactions(payload)
+	require.NoError(t, err)
+	require.Len(t, decoded, 2)
+	for i := range calls {
+		assert.Equal(t, calls[i].To, decoded[i].To)
+		assert.Equal(t, calls[i].Data, decoded[i].Data)
+		assert.Zero(t, calls[i].Value.Cmp(decoded[i].Value))
+	}
+
+	approve := DecodeCall(decoded[0])
+	assert.Equal(t, AInkWlWETH, approve.To)
+	assert.Equal(t, "approve", approve.Method)
+	assert.Equal(t, []string{"spender=" + GateWayV3.Hex(), "amount=1000000000000000000"}, approve.Args)
+
+	withdraw := DecodeCall(decoded[1])
+	assert.Equal(t, "withdrawETH", withdraw.Method)
+	assert.Contains(t, withdraw.Args, "to="+to.Hex())
+
+	_, err = DecodeSafeExecTransactions(datas[0])
+	assert.Error(t, err)
+}
+
+// TestDecodeCall_Unknown 测试未知方法只返回选择器
+func TestDecodeCall_Unknown(t *testing.T) {
+	data := crypto.Keccak256([]byte("unknownMethod()"))[:4]
+	decoded := DecodeCall(Call{To: WETH, Value: big.NewInt(0), Data: data})
+	assert.Equal(t, "0x"+common.Bytes2Hex(data), decoded.Method)
+	assert.Empty(t, decoded.Args)
+}
+
+// TestDecodeArgs 测试未命名参数按位置解码，不会互相覆盖
+func TestDecodeArgs(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"function","name":"transfer","inputs":[{"name":"","type":"address"},{"name":"","type":"uint256"},{"name":"memo","type":"bytes"}],"outputs":[]}]`))
+	require.NoError(t, err)
+	method := parsed.Methods["transfer"]
+
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	data, err := method.Inputs.Pack(to, big.NewInt(42), []byte{0xab})
+	require.NoError(t, err)
+
+	args, err := decodeArgs(&method, data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arg0=" + to.Hex(), "arg1=42", "memo=0xab"}, args)
+
+	_, err = decodeArgs(&method, data[:10])
+	assert.Error(t, err)
+}
+
+// TestDecodeRevertData 测试 revert 数据解析
+func TestDecodeRevertData(t *testing.T) {
+	// Error(string) "insufficient"
+	reason := common.FromHex("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000c" +
+		"696e73756666696369656e740000000000000000000000000000000000000000")
+	assert.Equal(t, "insufficient", DecodeRevertData(reason))
+
+	// aToken 自定义错误 ZeroAddressNotValid()
+	custom := crypto.Keccak256([]byte("ZeroAddressNotValid()"))[:4]
+	assert.Equal(t, "ZeroAddressNotValid[]", DecodeRevertData(custom))
+
+	assert.Equal(t, "0xdeadbeef", DecodeRevertData(common.FromHex("0xdeadbeef")))
+}
@@ -0,0 +1,343 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// 交易最终状态
+const (
+	TxStatusSuccess   = "success"   // 已上链且执行成功
+	TxStatusReverted  = "reverted"  // 已上链但执行失败
+	TxStatusPending   = "pending"   // 超时仍未上链，结果未知
+	TxStatusFailed    = "failed"    // 未能发送
+	TxStatusSimulated = "simulated" // 模拟模式，未广播
+)
+
+// 交易生命周期默认参数
+const (
+	DefaultReceiptTimeout  = 120 * time.Second
+	DefaultMaxReplacements = 3
+	DefaultFeeBumpPercent  = 20 // 节点要求替换交易至少提高 10% 手续费
+	DefaultReceiptPoll     = 2 * time.Second
+	minGasLimit            = uint64(3000000)
+)
+
+// TxOptions 交易生命周期参数
+type TxOptions struct {
+	ReceiptTimeout  time.Duration // 每次发送后等待回执的时间
+	MaxReplacements int           // 超时未上链时使用相同 nonce 提价重发的最大次数
+	FeeBumpPercent  int           // 每次重发提高手续费的百分比
+	PollInterval    time.Duration // 查询回执的间隔
+}
+
+// withDefaults 补全未设置的参数，MaxReplacements 为 0 时不重发
+func (o TxOptions) withDefaults() TxOptions {
+	if o.ReceiptTimeout <= 0 {
+		o.ReceiptTimeout = DefaultReceiptTimeout
+	}
+	if o.MaxReplacements < 0 {
+		o.MaxReplacements = 0
+	}
+	if o.FeeBumpPercent < 10 {
+		o.FeeBumpPercent = DefaultFeeBumpPercent
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultReceiptPoll
+	}
+	return o
+}
+
+// TxResult 交易的最终结果
+type TxResult struct {
+	Hash         common.Hash    // 最终上链（或最后一次发送）的交易哈希
+	Hashes       []common.Hash  // 所有发送过的交易哈希（含替换交易）
+	Nonce        uint64         // 交易 nonce
+	Status       string         // 交易最终状态
+	Receipt      *types.Receipt // 上链后的回执
+	RevertReason string         // 执行失败时解析出的 revert 原因
+	Replacements int            // 提价重发次数
+}
+
+// ExecCallsAndWait 将多个调用打包为 Safe execTransactions 发送，并等待交易上链
+func (d *Delegate) ExecCallsAndWait(ctx context.Context, calls []Call, opts TxOptions) (*TxResult, error) {
+	var addrs []common.Address
+	var values []*big.Int
+	var datas [][]byte
+	for _, call := range calls {
+		addrs = append(addrs, call.To)
+		values = append(values, call.Value)
+		datas = append(datas, call.Data)
+	}
+
+	safeExecData, err := buildSafeExecTransactions(addrs, values, datas)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.SendAndWait(ctx, d.argus, big.NewInt(0), safeExecData, opts)
+}
+
+// SendAndWait 发送交易并等待回执
+// 超时未上链时使用相同 nonce 提高手续费重发，任意一笔上链即结束；
+// 执行失败时在上链区块的父区块重放调用以解析 revert 原因；
+// 签名后的错误只有在确认交易未被节点接收时才返回 failed，否则结果未知，返回 pending
+func (d *Delegate) SendAndWait(ctx context.Context, to common.Address, value *big.Int, data []byte, opts TxOptions) (*TxResult, error) {
+	opts = opts.withDefaults()
+
+	msg := ethereum.CallMsg{From: d.bot, To: &to, Value: value, Data: data}
+
+	nonce, err := d.client.PendingNonceAt(ctx, d.bot)
+	if err != nil {
+		return &TxResult{Status: TxStatusFailed}, fmt.Errorf("获取 nonce 失败: %w", err)
+	}
+	chainID, err := d.client.ChainID(ctx)
+	if err != nil {
+		return &TxResult{Status: TxStatusFailed}, fmt.Errorf("获取 chain ID 失败: %w", err)
+	}
+	gasLimit, err := d.client.EstimateGas(ctx, msg)
+	if err != nil {
+		return &TxResult{Status: TxStatusFailed}, fmt.Errorf("估算 gas 失败: %s", DecodeRevert(err))
+	}
+	if gasLimit < minGasLimit {
+		gasLimit = minGasLimit
+	}
+	gasTipCap, gasFeeCap, err := d.suggestFees(ctx)
+	if err != nil {
+		return &TxResult{Status: TxStatusFailed}, err
+	}
+
+	result := &TxResult{Nonce: nonce, Status: TxStatusPending}
+	for attempt := 0; attempt <= opts.MaxReplacements; attempt++ {
+		if attempt > 0 {
+			gasTipCap, gasFeeCap, err = d.bumpFees(ctx, gasTipCap, gasFeeCap, opts.FeeBumpPercent)
+			if err != nil {
+				return result, err
+			}
+			result.Replacements = attempt
+		}
+
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       gasLimit,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		})
+		signedTx, err := d.signer.SignTx(ctx, tx, chainID)
+		if err != nil {
+			if len(result.Hashes) == 0 {
+				result.Status = TxStatusFailed
+			}
+			return result, fmt.Errorf("签名交易失败: %w", err)
+		}
+
+		if err := d.client.SendTransaction(ctx, signedTx); err != nil {
+			switch {
+			case isUnderpriced(err):
+				// 手续费不足以替换，直接进入下一轮提价
+				d.logger.Warn("交易手续费不足，提价重发", zap.Uint64("nonce", nonce), zap.Error(err))
+				continue
+			case isAlreadyKnown(err):
+				// 节点的交易池中已有这笔交易（如之前的发送超时但已被接收），按发送成功处理
+				result.Hashes = append(result.Hashes, signedTx.Hash())
+				result.Hash = signedTx.Hash()
+				d.logger.Info("交易已在交易池中",
+					zap.String("tx_hash", signedTx.Hash().Hex()),
+					zap.Uint64("nonce", nonce),
+					zap.Int("attempt", attempt),
+				)
+			case isNonceUsed(err):
+				// nonce 已被之前发送的交易使用，继续等待已有交易的回执
+				d.logger.Debug("nonce 已被使用，等待已发送交易的回执", zap.Uint64("nonce", nonce), zap.Error(err))
+			case d.notBroadcast(ctx, signedTx.Hash(), nonce):
+				if len(result.Hashes) == 0 {
+					result.Status = TxStatusFailed
+				}
+				return result, fmt.Errorf("发送交易失败: %w", err)
+			default:
+				// 发送返回错误（如超时）时交易仍可能已被节点接收，按已发送处理并等待回执
+				result.Hashes = append(result.Hashes, signedTx.Hash())
+				result.Hash = signedTx.Hash()
+				d.logger.Warn("发送交易返回错误，交易可能已被接收，继续等待回执",
+					zap.String("tx_hash", signedTx.Hash().Hex()),
+					zap.Uint64("nonce", nonce),
+					zap.Int("attempt", attempt),
+					zap.Error(err),
+				)
+			}
+		} else {
+			result.Hashes = append(result.Hashes, signedTx.Hash())
+			result.Hash = signedTx.Hash()
+			d.logger.Info("交易已发送",
+				zap.String("tx_hash", signedTx.Hash().Hex()),
+				zap.Uint64("nonce", nonce),
+				zap.Int("attempt", attempt),
+				zap.String("gas_tip_cap", gasTipCap.String()),
+				zap.String("gas_fee_cap", gasFeeCap.String()),
+			)
+		}
+
+		receipt, err := d.waitReceipt(ctx, result.Hashes, opts)
+		if err != nil {
+			return result, err
+		}
+		if receipt != nil {
+			return d.finalize(ctx, result, receipt, msg)
+		}
+	}
+
+	if len(result.Hashes) == 0 {
+		// 每次发送都因手续费不足被拒绝，交易没有被节点接收
+		result.Status = TxStatusFailed
+		return result, fmt.Errorf("交易在 %d 次提价重发后手续费仍不足，未被节点接收 (nonce %d)", result.Replacements, nonce)
+	}
+	return result, fmt.Errorf("交易在 %d 次提价重发后仍未上链 (nonce %d)", result.Replacements, nonce)
+}
+
+// notBroadcast 确认交易没有被节点接收：节点查不到这笔交易，且 nonce 没有被已上链的交易使用
+// 查询失败时无法确认，返回 false
+func (d *Delegate) notBroadcast(ctx context.Context, hash common.Hash, nonce uint64) bool {
+	if _, _, err := d.client.TransactionByHash(ctx, hash); !errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	latest, err := d.client.NonceAt(ctx, d.bot, nil)
+	return err == nil && latest <= nonce
+}
+
+// waitReceipt 在超时时间内轮询所有已发送交易的回执，超时返回 nil
+func (d *Delegate) waitReceipt(ctx context.Context, hashes []common.Hash, opts TxOptions) (*types.Receipt, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	deadline := time.Now().Add(opts.ReceiptTimeout)
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, hash := range hashes {
+			receipt, err := d.client.TransactionReceipt(ctx, hash)
+			if err == nil {
+				return receipt, nil
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				return nil, fmt.Errorf("查询交易回执失败: %w", err)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// finalize 根据回执确定交易最终状态
+func (d *Delegate) finalize(ctx context.Context, result *TxResult, receipt *types.Receipt, msg ethereum.CallMsg) (*TxResult, error) {
+	result.Receipt = receipt
+	result.Hash = receipt.TxHash
+
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		result.Status = TxStatusSuccess
+		return result, nil
+	}
+
+	result.Status = TxStatusReverted
+	result.RevertReason = d.revertReason(ctx, msg, receipt.BlockNumber)
+	return result, fmt.Errorf("交易执行失败 %s: %s", receipt.TxHash.Hex(), result.RevertReason)
+}
+
+// revertReason 在交易所在区块的父区块重放调用，解析 revert 原因
+func (d *Delegate) revertReason(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) string {
+	var block *big.Int
+	if blockNumber != nil && blockNumber.Sign() > 0 {
+		block = new(big.Int).Sub(blockNumber, big.NewInt(1))
+	}
+	_, err := d.client.CallContract(ctx, msg, block)
+	if err == nil {
+		return "重放调用未复现失败（可能受同区块内其他交易影响）"
+	}
+	return DecodeRevert(err)
+}
+
+// suggestFees 获取 EIP-1559 手续费建议: maxFee = baseFee * 2 + tip
+func (d *Delegate) suggestFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	gasTipCap, err := d.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取 gas tip 失败: %w", err)
+	}
+	header, err := d.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取最新区块失败: %w", err)
+	}
+	gasFeeCap := new(big.Int).Add(
+		gasTipCap,
+		new(big.Int).Mul(header.BaseFee, big.NewInt(2)),
+	)
+	return gasTipCap, gasFeeCap, nil
+}
+
+// bumpFees 按百分比提高手续费，并且不低于当前的建议值
+func (d *Delegate) bumpFees(ctx context.Context, tip, feeCap *big.Int, percent int) (*big.Int, *big.Int, error) {
+	newTip := bumpPercent(tip, percent)
+	newFeeCap := bumpPercent(feeCap, percent)
+
+	suggestedTip, suggestedFeeCap, err := d.suggestFees(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if suggestedTip.Cmp(newTip) > 0 {
+		newTip = suggestedTip
+	}
+	if suggestedFeeCap.Cmp(newFeeCap) > 0 {
+		newFeeCap = suggestedFeeCap
+	}
+	if newFeeCap.Cmp(newTip) < 0 {
+		newFeeCap = new(big.Int).Set(newTip)
+	}
+	return newTip, newFeeCap, nil
+}
+
+// bumpPercent 返回 v * (100 + percent) / 100，至少加 1
+func bumpPercent(v *big.Int, percent int) *big.Int {
+	bumped := new(big.Int).Mul(v, big.NewInt(int64(100+percent)))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(v) <= 0 {
+		bumped.Add(v, big.NewInt(1))
+	}
+	return bumped
+}
+
+// isUnderpriced 判断是否为手续费不足导致的发送失败
+func isUnderpriced(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "underpriced") || strings.Contains(msg, "fee too low")
+}
+
+// isAlreadyKnown 判断节点的交易池中是否已有这笔交易
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "already known")
+}
+
+// isNonceUsed 判断 nonce 是否已被之前发送的交易使用
+func isNonceUsed(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
@@ -0,0 +1,258 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// txRevertError 模拟 eth_call 重放时返回的 revert 错误，原因为 "insufficient"
+type txRevertError struct{}
+
+func (txRevertError) Error() string  { return "execution reverted" }
+func (txRevertError) ErrorCode() int { return 3 }
+func (txRevertError) ErrorData() interface{} {
+	return "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000c" +
+		"696e73756666696369656e740000000000000000000000000000000000000000"
+}
+
+// txNode 模拟交易相关的节点接口
+type txNode struct {
+	mu       sync.Mutex
+	nonce    uint64               // pending nonce
+	latest   uint64               // 已上链的 nonce
+	sendErr  error                // eth_sendRawTransaction 返回的错误
+	accept   bool                 // 返回错误时仍接收交易（如发送超时）
+	mine     bool                 // 是否为交易生成回执
+	status   uint64               // 回执状态
+	received []*types.Transaction // 节点收到的交易
+}
+
+func (n *txNode) ChainId() *hexutil.Big { return (*hexutil.Big)(big.NewInt(InkChainID)) }
+
+func (n *txNode) GetTransactionCount(addr common.Address, block string) hexutil.Uint64 {
+	if block == "pending" {
+		return hexutil.Uint64(n.nonce)
+	}
+	return hexutil.Uint64(n.latest)
+}
+
+func (n *txNode) EstimateGas(args map[string]interface{}, block *string) hexutil.Uint64 {
+	return 100000
+}
+
+func (n *txNode) MaxPriorityFeePerGas() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1e9)) }
+
+func (n *txNode) GetBlockByNumber(number string, full bool) *types.Header {
+	return &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(1e9), Difficulty: big.NewInt(0)}
+}
+
+func (n *txNode) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.sendErr == nil || n.accept {
+		n.received = append(n.received, tx)
+	}
+	return tx.Hash(), n.sendErr
+}
+
+func (n *txNode) find(hash common.Hash) *types.Transaction {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, tx := range n.received {
+		if tx.Hash() == hash {
+			return tx
+		}
+	}
+	return nil
+}
+
+func (n *txNode) GetTransactionByHash(hash common.Hash) interface{} {
+	if tx := n.find(hash); tx != nil {
+		return tx
+	}
+	return nil
+}
+
+func (n *txNode) GetTransactionReceipt(hash common.Hash) interface{} {
+	if !n.mine || n.find(hash) == nil {
+		return nil
+	}
+	return &types.Receipt{
+		Status:      n.status,
+		TxHash:      hash,
+		BlockNumber: big.NewInt(101),
+		GasUsed:     90000,
+		Logs:        []*types.Log{},
+	}
+}
+
+func (n *txNode) Call(args map[string]interface{}, block interface{}) (hexutil.Bytes, error) {
+	return nil, txRevertError{}
+}
+
+// newTestDelegate 创建连接模拟节点的委托人
+func newTestDelegate(t *testing.T, node *txNode) *Delegate {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	rpcClient, err := rpc.Dial(httpServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(rpcClient.Close)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := newKeySigner(key)
+	return &Delegate{
+		client: ethclient.NewClient(rpcClient),
+		bot:    signer.Address(),
+		signer: signer,
+		logger: zap.NewNop(),
+	}
+}
+
+// fastTx 测试使用的交易生命周期参数
+var fastTx = TxOptions{ReceiptTimeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond, MaxReplacements: -1}
+
+// TestSendAndWait 测试交易的最终状态
+func TestSendAndWait(t *testing.T) {
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	t.Run("上链成功", func(t *testing.T) {
+		node := &txNode{nonce: 5, latest: 5, mine: true, status: types.ReceiptStatusSuccessful}
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, fastTx)
+		require.NoError(t, err)
+		assert.Equal(t, TxStatusSuccess, result.Status)
+		assert.Equal(t, uint64(5), result.Nonce)
+		assert.Len(t, result.Hashes, 1)
+	})
+
+	t.Run("执行失败", func(t *testing.T) {
+		node := &txNode{mine: true, status: types.ReceiptStatusFailed}
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, fastTx)
+		assert.Error(t, err)
+		assert.Equal(t, TxStatusReverted, result.Status)
+		assert.Contains(t, result.RevertReason, "insufficient")
+	})
+
+	t.Run("节点拒绝交易", func(t *testing.T) {
+		node := &txNode{sendErr: errors.New("insufficient funds for gas * price + value")}
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, fastTx)
+		assert.ErrorContains(t, err, "发送交易失败")
+		assert.Equal(t, TxStatusFailed, result.Status)
+		assert.Empty(t, result.Hashes)
+	})
+
+	t.Run("发送返回错误但交易已被接收", func(t *testing.T) {
+		node := &txNode{sendErr: errors.New("context deadline exceeded"), accept: true, mine: true, status: types.ReceiptStatusSuccessful}
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, fastTx)
+		require.NoError(t, err)
+		assert.Equal(t, TxStatusSuccess, result.Status)
+		assert.Len(t, result.Hashes, 1)
+	})
+
+	t.Run("发送返回错误且 nonce 已被使用", func(t *testing.T) {
+		node := &txNode{nonce: 3, latest: 4, sendErr: errors.New("connection reset")}
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, fastTx)
+		assert.Error(t, err)
+		assert.Equal(t, TxStatusPending, result.Status, "无法确认交易未发送时结果未知")
+		assert.Len(t, result.Hashes, 1)
+	})
+
+	t.Run("交易已在交易池中", func(t *testing.T) {
+		node := &txNode{sendErr: errors.New("already known"), accept: true, mine: true, status: types.ReceiptStatusSuccessful}
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, fastTx)
+		require.NoError(t, err)
+		assert.Equal(t, TxStatusSuccess, result.Status)
+		require.Len(t, result.Hashes, 1)
+		assert.Equal(t, node.received[0].Hash(), result.Hash)
+	})
+
+	t.Run("提价后手续费仍不足", func(t *testing.T) {
+		node := &txNode{sendErr: errors.New("replacement transaction underpriced")}
+		opts := fastTx
+		opts.MaxReplacements = 1
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, opts)
+		assert.ErrorContains(t, err, "未被节点接收")
+		assert.Equal(t, TxStatusFailed, result.Status, "没有发送任何交易时不是未知结果")
+		assert.Empty(t, result.Hashes)
+	})
+
+	t.Run("回执超时", func(t *testing.T) {
+		node := &txNode{}
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, fastTx)
+		assert.ErrorContains(t, err, "仍未上链")
+		assert.Equal(t, TxStatusPending, result.Status)
+		assert.Len(t, result.Hashes, 1)
+		assert.Zero(t, result.Replacements)
+	})
+
+	t.Run("提价重发后仍未上链", func(t *testing.T) {
+		node := &txNode{nonce: 7}
+		opts := fastTx
+		opts.MaxReplacements = 2
+		result, err := newTestDelegate(t, node).SendAndWait(context.Background(), to, big.NewInt(0), nil, opts)
+		assert.ErrorContains(t, err, "2 次提价重发后仍未上链")
+		assert.Equal(t, TxStatusPending, result.Status)
+		assert.Equal(t, 2, result.Replacements)
+		require.Len(t, node.received, 3)
+		for i, tx := range node.received {
+			assert.Equal(t, uint64(7), tx.Nonce(), "替换交易使用相同 nonce")
+			if i > 0 {
+				assert.Equal(t, 1, tx.GasFeeCap().Cmp(node.received[i-1].GasFeeCap()), "每次重发提高手续费")
+			}
+		}
+		assert.Equal(t, node.received[2].Hash(), result.Hash)
+	})
+}
+
+// TestBumpPercent 测试替换交易的手续费提升
+func TestBumpPercent(t *testing.T) {
+	assert.Equal(t, big.NewInt(120), bumpPercent(big.NewInt(100), 20))
+	assert.Equal(t, big.NewInt(1), bumpPercent(big.NewInt(0), 20), "至少提高 1 wei")
+	assert.Equal(t, big.NewInt(2), bumpPercent(big.NewInt(1), 10))
+}
+
+// TestTxOptionsDefaults 测试交易生命周期默认参数
+func TestTxOptionsDefaults(t *testing.T) {
+	opts := TxOptions{MaxReplacements: -1, FeeBumpPercent: 5}.withDefaults()
+	assert.Equal(t, DefaultReceiptTimeout, opts.ReceiptTimeout)
+	assert.Equal(t, 0, opts.MaxReplacements)
+	assert.Equal(t, DefaultFeeBumpPercent, opts.FeeBumpPercent, "低于节点替换要求时使用默认值")
+	assert.Equal(t, DefaultReceiptPoll, opts.PollInterval)
+
+	opts = TxOptions{ReceiptTimeout: time.Minute, FeeBumpPercent: 50}.withDefaults()
+	assert.Equal(t, time.Minute, opts.ReceiptTimeout)
+	assert.Equal(t, 50, opts.FeeBumpPercent)
+}
+
+// TestSendErrorClassification 测试发送错误分类
+func TestSendErrorClassification(t *testing.T) {
+	assert.True(t, isUnderpriced(errors.New("replacement transaction underpriced")))
+	assert.True(t, isUnderpriced(errors.New("max fee per gas less than block base fee: fee too low")))
+	assert.False(t, isUnderpriced(errors.New("nonce too low")))
+	assert.True(t, isNonceUsed(errors.New("nonce too low")))
+	assert.True(t, isAlreadyKnown(errors.New("already known")))
+	assert.False(t, isNonceUsed(errors.New("already known")), "交易池中已有的交易按发送成功处理")
+}
@@ -15,6 +15,7 @@ const (
 	TypePriceFeed       = "price_feed"
 	TypeGetPaused       = "get_paused"
 	TypeReserveCap      = "reserve_cap"
+	TypeABICall         = "abi_call"
 )
 
 // 默认合约地址常量
@@ -41,6 +42,22 @@ type Account interface {
 	Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error)
 }
 
+// Reading 合约一次读取的完整结果
+type Reading struct {
+	Value    float64            // 指标值
+	Details  map[string]float64 // 附加指标，按指标名称后缀索引
+	Warnings []string           // 读取结果存在的问题，如价格过期
+}
+
+// DetailedAccount 除指标值外还导出附加指标的合约
+type DetailedAccount interface {
+	Account
+	// Read 执行监控并返回指标值和附加指标，与 Monitor 读取相同的数据
+	Read(ctx context.Context, caller *client.ContractCaller) (*Reading, error)
+	// DetailNames 返回导出的附加指标名称后缀，启动时用于校验关联了应急规则的附加指标
+	DetailNames() []string
+}
+
 // BaseContract 基础合约结构
 type BaseContract struct {
 	name     string
@@ -0,0 +1,298 @@
+package emergency
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// 应急动作类型
+const (
+	ActionGatewayWithdrawETH = "gateway_withdraw_eth" // 从 Aave GatewayV3 取出 ETH
+	ActionBridgeWithdrawWETH = "bridge_withdraw_weth" // 通过 L2StandardBridge 将 WETH 提回 L1
+	ActionRepayDebt          = "repay_debt"           // 通过 GatewayV3 用 ETH 偿还债务
+	ActionNotify             = "notify"               // 仅记录告警
+)
+
+// DefaultActionName 规则未指定动作时使用的动作，由 emergency.withdraw_amount 生成
+const DefaultActionName = "withdraw_eth"
+
+// DefaultActionCooldown 默认动作 withdraw_eth 的冷却时间（秒），重新布防后也不会立即再次提款
+const DefaultActionCooldown = 3600
+
+// Action 应急动作
+type Action interface {
+	Name() string
+	Type() string
+	// Cooldown 返回动作的冷却时间
+	Cooldown() time.Duration
+	// Execute 执行动作，reason 为触发原因
+	// 交易类动作返回交易的最终结果，其他动作返回 nil
+	Execute(ctx context.Context, reason string) (*contracts.TxResult, error)
+	// Preview 模拟执行动作但不广播，交易类动作返回模拟结果，其他动作返回 nil
+	Preview(ctx context.Context) (*contracts.SimulationResult, error)
+}
+
+// txAction 通过 Safe execTransactions 执行的动作
+type txAction struct {
+	name     string
+	typeName string
+	cooldown time.Duration
+	calls    []contracts.Call
+	dryRun   bool
+	txOpts   contracts.TxOptions
+	delegate *contracts.Delegate
+	logger   *zap.Logger
+}
+
+func (a *txAction) Name() string { return a.name }
+func (a *txAction) Type() string { return a.typeName }
+
+func (a *txAction) Cooldown() time.Duration { return a.cooldown }
+
+// Execute 批量发送动作包含的调用并等待交易上链，模拟模式下只模拟不广播
+func (a *txAction) Execute(ctx context.Context, reason string) (*contracts.TxResult, error) {
+	if a.dryRun {
+		return &contracts.TxResult{Status: contracts.TxStatusSimulated}, a.simulate(ctx, reason)
+	}
+
+	result, err := a.delegate.ExecCallsAndWait(ctx, a.calls, a.txOpts)
+	if result != nil {
+		fields := []zap.Field{
+			zap.String("action", a.name),
+			zap.String("type", a.typeName),
+			zap.String("reason", reason),
+			zap.String("status", result.Status),
+			zap.String("tx_hash", result.Hash.Hex()),
+			zap.Uint64("nonce", result.Nonce),
+			zap.Int("replacements", result.Replacements),
+		}
+		if result.Receipt != nil {
+			fields = append(fields,
+				zap.Uint64("block", result.Receipt.BlockNumber.Uint64()),
+				zap.Uint64("gas_used", result.Receipt.GasUsed),
+			)
+		}
+		switch result.Status {
+		case contracts.TxStatusSuccess:
+			a.logger.Info("应急交易已上链", fields...)
+		case contracts.TxStatusReverted:
+			a.logger.Error("应急交易执行失败", append(fields, zap.String("revert_reason", result.RevertReason))...)
+		case contracts.TxStatusPending:
+			a.logger.Error("应急交易未能在超时时间内上链", append(fields, zap.Int("sent", len(result.Hashes)))...)
+		}
+	}
+	return result, err
+}
+
+// Preview 模拟执行动作包含的调用，返回解码后的内部调用和模拟结果
+func (a *txAction) Preview(ctx context.Context) (*contracts.SimulationResult, error) {
+	result, err := a.delegate.Simulate(ctx, a.calls)
+	if err != nil {
+		return nil, fmt.Errorf("模拟应急交易失败: %w", err)
+	}
+	return result, nil
+}
+
+// simulate 模拟执行并记录解码后的内部调用和模拟结果
+func (a *txAction) simulate(ctx context.Context, reason string) error {
+	result, err := a.Preview(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, call := range result.Calls {
+		a.logger.Info("模拟应急交易内部调用",
+			zap.String("action", a.name),
+			zap.Int("index", i),
+			zap.String("to", call.To.Hex()),
+			zap.String("method", call.Method),
+			zap.Strings("args", call.Args),
+			zap.String("value", call.Value.String()),
+		)
+	}
+
+	fields := []zap.Field{
+		zap.String("action", a.name),
+		zap.String("type", a.typeName),
+		zap.String("reason", reason),
+		zap.String("from", result.From.Hex()),
+		zap.String("to", result.To.Hex()),
+		zap.Bool("success", result.Success),
+		zap.Uint64("gas_estimate", result.GasEstimate),
+	}
+	if !result.Success {
+		a.logger.Error("模拟应急交易失败，交易不会成功", append(fields, zap.String("error", result.Error))...)
+		return fmt.Errorf("模拟应急交易失败: %s", result.Error)
+	}
+
+	a.logger.Info("模拟应急交易成功（未广播）", fields...)
+	return nil
+}
+
+// notifyAction 仅记录日志的动作
+type notifyAction struct {
+	name     string
+	cooldown time.Duration
+	logger   *zap.Logger
+}
+
+func (a *notifyAction) Name() string { return a.name }
+func (a *notifyAction) Type() string { return ActionNotify }
+
+func (a *notifyAction) Cooldown() time.Duration { return a.cooldown }
+
+// Execute 记录告警通知
+func (a *notifyAction) Execute(ctx context.Context, reason string) (*contracts.TxResult, error) {
+	a.logger.Warn("应急通知", zap.String("action", a.name), zap.String("reason", reason))
+	return nil, nil
+}
+
+// Preview 通知动作没有交易，无需模拟
+func (a *notifyAction) Preview(ctx context.Context) (*contracts.SimulationResult, error) {
+	return nil, nil
+}
+
+// NewActions 根据配置创建应急动作注册表
+// 配置了 withdraw_amount 且未显式定义同名动作时，注册默认动作 withdraw_eth，冷却时间为 DefaultActionCooldown
+func NewActions(cfg *config.EmergencyConfig, delegate *contracts.Delegate, logger *zap.Logger) (map[string]Action, error) {
+	cfgs := cfg.Actions
+	if cfg.WithdrawAmount != "" && !hasAction(cfgs, DefaultActionName) {
+		cfgs = append(cfgs, config.ActionConfig{
+			Name:     DefaultActionName,
+			Type:     ActionGatewayWithdrawETH,
+			Amount:   cfg.WithdrawAmount,
+			Cooldown: DefaultActionCooldown,
+		})
+	}
+
+	actions := make(map[string]Action, len(cfgs))
+	for i, actionCfg := range cfgs {
+		if actionCfg.Name == "" {
+			return nil, fmt.Errorf("emergency.actions[%d].name 不能为空", i)
+		}
+		if _, exists := actions[actionCfg.Name]; exists {
+			return nil, fmt.Errorf("emergency.actions[%d]: 动作名称重复: %s", i, actionCfg.Name)
+		}
+		action, err := newAction(actionCfg, cfg.DryRun, txOptions(cfg), delegate, logger)
+		if err != nil {
+			return nil, fmt.Errorf("emergency.actions[%d]: %w", i, err)
+		}
+		actions[actionCfg.Name] = action
+	}
+	return actions, nil
+}
+
+// checkRuleActions 检查规则引用的动作都已定义
+func checkRuleActions(rules *RuleEngine, actions map[string]Action) error {
+	for _, rule := range rules.Rules() {
+		for _, name := range rule.Actions {
+			if _, ok := actions[name]; !ok {
+				return fmt.Errorf("规则 %s 引用了不存在的动作 %s", rule.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// checkChainRules 检查链存活指标只关联通知动作。这些指标只从单个RPC节点读取，
+// head_lag_blocks 还取所有节点中的最大值，一个落后的备用节点就会让规则命中，不能用来发起交易
+func checkChainRules(rules *RuleEngine, actions map[string]Action) error {
+	for _, chain := range []string{"ethereum", "ink"} {
+		for _, check := range metrics.ChainChecks(chain) {
+			metricName := metrics.GetChainMetricName(chain, check)
+			for _, rule := range rules.Matching(metricName) {
+				for _, name := range rule.Actions {
+					if action, ok := actions[name]; ok && action.Type() != ActionNotify {
+						return fmt.Errorf("规则 %s 匹配链存活指标 %s，只能关联 notify 动作，不能关联 %s", rule.Name, metricName, name)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// newAction 创建单个应急动作，交易类动作的调用数据在启动时构造
+func newAction(cfg config.ActionConfig, dryRun bool, txOpts contracts.TxOptions, delegate *contracts.Delegate, logger *zap.Logger) (Action, error) {
+	if cfg.Type == ActionNotify {
+		return &notifyAction{name: cfg.Name, cooldown: cfg.GetCooldown(), logger: logger}, nil
+	}
+
+	amount, ok := new(big.Int).SetString(cfg.Amount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("动作 %s 的 amount 无效: %q", cfg.Name, cfg.Amount)
+	}
+
+	recipient := delegate.Safe()
+	if cfg.Recipient != "" {
+		if !common.IsHexAddress(cfg.Recipient) {
+			return nil, fmt.Errorf("动作 %s 的 recipient 不是有效地址: %q", cfg.Name, cfg.Recipient)
+		}
+		recipient = common.HexToAddress(cfg.Recipient)
+	}
+
+	var (
+		calls []contracts.Call
+		err   error
+	)
+	switch cfg.Type {
+	case ActionGatewayWithdrawETH:
+		calls, err = contracts.BuildGatewayWithdrawETHCalls(amount, recipient)
+	case ActionBridgeWithdrawWETH:
+		minGasLimit := cfg.MinGasLimit
+		if minGasLimit == 0 {
+			minGasLimit = contracts.DefaultBridgeMinGasLimit
+		}
+		calls, err = contracts.BuildBridgeWithdrawWETHCalls(amount, recipient, minGasLimit)
+	case ActionRepayDebt:
+		calls, err = contracts.BuildGatewayRepayETHCalls(amount, recipient)
+	default:
+		return nil, fmt.Errorf("动作 %s 的类型不支持: %q", cfg.Name, cfg.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("构造动作 %s 的调用数据失败: %w", cfg.Name, err)
+	}
+
+	return &txAction{
+		name:     cfg.Name,
+		typeName: cfg.Type,
+		cooldown: cfg.GetCooldown(),
+		calls:    calls,
+		dryRun:   dryRun,
+		txOpts:   txOpts,
+		delegate: delegate,
+		logger:   logger,
+	}, nil
+}
+
+// txOptions 根据配置生成交易生命周期参数，max_replacements 为 0 时使用默认值，负数表示不重发
+func txOptions(cfg *config.EmergencyConfig) contracts.TxOptions {
+	maxReplacements := cfg.MaxReplacements
+	if maxReplacements == 0 {
+		maxReplacements = contracts.DefaultMaxReplacements
+	}
+	return contracts.TxOptions{
+		ReceiptTimeout:  cfg.GetReceiptTimeout(),
+		MaxReplacements: maxReplacements,
+		FeeBumpPercent:  cfg.FeeBumpPercent,
+	}
+}
+
+// hasAction 检查是否已定义指定名称的动作
+func hasAction(cfgs []config.ActionConfig, name string) bool {
+	for _, cfg := range cfgs {
+		if cfg.Name == name {
+			return true
+		}
+	}
+	return false
+}
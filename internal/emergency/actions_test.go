@@ -0,0 +1,163 @@
+package emergency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+)
+
+// TestNewActions 测试根据配置创建应急动作
+func TestNewActions(t *testing.T) {
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tests := []struct {
+		name    string
+		cfg     config.EmergencyConfig
+		want    map[string]string // 动作名称 -> 类型
+		wantErr string
+	}{
+		{
+			name: "默认动作",
+			cfg:  config.EmergencyConfig{WithdrawAmount: "1000"},
+			want: map[string]string{DefaultActionName: ActionGatewayWithdrawETH},
+		},
+		{
+			name: "未配置提款金额时没有默认动作",
+			cfg:  config.EmergencyConfig{},
+			want: map[string]string{},
+		},
+		{
+			name: "显式定义的同名动作覆盖默认动作",
+			cfg: config.EmergencyConfig{WithdrawAmount: "1000", Actions: []config.ActionConfig{
+				{Name: DefaultActionName, Type: ActionNotify},
+			}},
+			want: map[string]string{DefaultActionName: ActionNotify},
+		},
+		{
+			name: "各类型动作",
+			cfg: config.EmergencyConfig{Actions: []config.ActionConfig{
+				{Name: "bridge", Type: ActionBridgeWithdrawWETH, Amount: "1000"},
+				{Name: "repay", Type: ActionRepayDebt, Amount: "1000", Recipient: recipient.Hex()},
+				{Name: "page", Type: ActionNotify, Cooldown: 60},
+			}},
+			want: map[string]string{"bridge": ActionBridgeWithdrawWETH, "repay": ActionRepayDebt, "page": ActionNotify},
+		},
+		{
+			name: "动作名称重复",
+			cfg: config.EmergencyConfig{Actions: []config.ActionConfig{
+				{Name: "page", Type: ActionNotify},
+				{Name: "page", Type: ActionNotify},
+			}},
+			wantErr: "动作名称重复",
+		},
+		{
+			name:    "动作名称为空",
+			cfg:     config.EmergencyConfig{Actions: []config.ActionConfig{{Type: ActionNotify}}},
+			wantErr: "name 不能为空",
+		},
+		{
+			name:    "未知类型",
+			cfg:     config.EmergencyConfig{Actions: []config.ActionConfig{{Name: "x", Type: "selfdestruct", Amount: "1"}}},
+			wantErr: "类型不支持",
+		},
+		{
+			name:    "金额无效",
+			cfg:     config.EmergencyConfig{Actions: []config.ActionConfig{{Name: "x", Type: ActionRepayDebt, Amount: "-1"}}},
+			wantErr: "amount 无效",
+		},
+		{
+			name:    "接收地址无效",
+			cfg:     config.EmergencyConfig{Actions: []config.ActionConfig{{Name: "x", Type: ActionRepayDebt, Amount: "1", Recipient: "0x12"}}},
+			wantErr: "recipient 不是有效地址",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions, err := NewActions(&tt.cfg, &contracts.Delegate{}, zap.NewNop())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			got := make(map[string]string, len(actions))
+			for name, action := range actions {
+				assert.Equal(t, name, action.Name())
+				got[name] = action.Type()
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// 默认动作带有冷却时间
+	actions, err := NewActions(&config.EmergencyConfig{WithdrawAmount: "1000"}, &contracts.Delegate{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(DefaultActionCooldown)*time.Second, actions[DefaultActionName].Cooldown())
+
+	actions, err = NewActions(&config.EmergencyConfig{Actions: []config.ActionConfig{
+		{Name: "bridge", Type: ActionBridgeWithdrawWETH, Amount: "1000", Cooldown: 300},
+	}}, &contracts.Delegate{}, zap.NewNop())
+	require.NoError(t, err)
+	bridge := actions["bridge"].(*txAction)
+	assert.Equal(t, 5*time.Minute, bridge.Cooldown())
+	assert.Len(t, bridge.calls, 2, "先授权 WETH 再调用 withdrawTo")
+}
+
+// TestCheckRuleActions 测试规则引用的动作必须已定义
+func TestCheckRuleActions(t *testing.T) {
+	actions := map[string]Action{"page": &notifyAction{name: "page"}}
+
+	rules, err := NewRuleEngine([]config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"page"}},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, checkRuleActions(rules, actions))
+
+	rules, err = NewRuleEngine([]config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"page", "missing"}},
+	})
+	require.NoError(t, err)
+	assert.ErrorContains(t, checkRuleActions(rules, actions), "规则 paused 引用了不存在的动作 missing")
+
+	// 未指定动作的规则使用默认动作，需要配置 withdraw_amount
+	rules, err = NewRuleEngine([]config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1},
+	})
+	require.NoError(t, err)
+	assert.ErrorContains(t, checkRuleActions(rules, actions), DefaultActionName)
+}
+
+// TestCheckChainRules 测试链存活指标只能关联通知动作
+func TestCheckChainRules(t *testing.T) {
+	actions := map[string]Action{
+		"page":            &notifyAction{name: "page"},
+		DefaultActionName: &txAction{name: DefaultActionName, typeName: ActionGatewayWithdrawETH},
+	}
+
+	rules, err := NewRuleEngine([]config.AlertRuleConfig{
+		{Name: "stalled", Metric: "ink_eth_monitor_ink_head_*", Operator: OpGreater, Threshold: 600, Actions: []string{"page"}},
+		{Name: "paused", Metric: "ink_eth_monitor_ink_paused", Operator: OpEqual, Threshold: 1},
+	})
+	require.NoError(t, err)
+	assert.NoError(t, checkChainRules(rules, actions))
+
+	// 未指定动作的规则使用默认的交易类动作
+	rules, err = NewRuleEngine([]config.AlertRuleConfig{
+		{Name: "lagging", Metric: "ink_eth_monitor_ethereum_head_lag_blocks", Operator: OpGreater, Threshold: 10},
+	})
+	require.NoError(t, err)
+	assert.ErrorContains(t, checkChainRules(rules, actions), "规则 lagging 匹配链存活指标 ink_eth_monitor_ethereum_head_lag_blocks")
+
+	// 通配符规则同样会匹配链存活指标
+	rules, err = NewRuleEngine([]config.AlertRuleConfig{
+		{Name: "any", Metric: "ink_eth_monitor_ink_*", Operator: OpGreater, Threshold: 10, Actions: []string{"page", DefaultActionName}},
+	})
+	require.NoError(t, err)
+	assert.ErrorContains(t, checkChainRules(rules, actions), "只能关联 notify 动作")
+}
@@ -1,8 +1,9 @@
 package emergency
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"math/big"
 	"sync"
 	"time"
 
@@ -10,31 +11,55 @@ import (
 
 	"cs-projects-ink-eth-monitor/internal/config"
 	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// 应急动作的执行结果，计入 ink_eth_monitor_emergency_actions_total
+// 与按交易最终状态计数的 emergency_tx_total 不同，每次规则命中或手动触发的每个动作都计入一次，
+// 包括通知动作、执行前失败（如签名、模拟失败）以及冷却期和解除布防时跳过的动作
+const (
+	OutcomeSuccess  = "success"
+	OutcomeFailed   = "failed"
+	OutcomePending  = "unknown"  // 交易已发送但超时未上链，结果未知，保持触发状态
+	OutcomeCooldown = "cooldown" // 冷却期内跳过
+	OutcomeDisarmed = "disarmed" // 规则临时解除布防，跳过
 )
 
 // Manager 应急响应管理器
 type Manager struct {
-	cfg             *config.EmergencyConfig
-	logger          *zap.Logger
-	delegate        *contracts.Delegate
-	triggered       bool
-	lastTriggerTime time.Time
-	mu              sync.Mutex
+	cfg      *config.EmergencyConfig
+	logger   *zap.Logger
+	delegate *contracts.Delegate
+	rules    *RuleEngine
+	actions  map[string]Action
+	state    *StateStore
+	metrics  *metrics.Metrics
+	mu       sync.Mutex
+	previews map[string]time.Time // 各动作最近一次预览成功的时间，手动触发前需要预览
 }
 
 // NewManager 创建应急响应管理器
-func NewManager(cfg *config.EmergencyConfig, inkRPC string, logger *zap.Logger) (*Manager, error) {
+func NewManager(ctx context.Context, cfg *config.EmergencyConfig, inkRPC string, metrics *metrics.Metrics, logger *zap.Logger) (*Manager, error) {
+	rules, err := NewRuleEngine(cfg.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("应急响应配置错误: %w", err)
+	}
+
 	if !cfg.Enabled {
-		logger.Info("应急响应功能未启用")
+		logger.Info("应急响应功能未启用，告警规则仅记录日志", zap.Int("rules", len(rules.Rules())))
+		state, _ := LoadStateStore("")
 		return &Manager{
-			cfg:    cfg,
-			logger: logger,
+			cfg:     cfg,
+			logger:  logger,
+			rules:   rules,
+			state:   state,
+			metrics: metrics,
 		}, nil
 	}
 
 	// 验证配置
-	if cfg.PrivateKey == "" {
-		return nil, fmt.Errorf("应急响应配置错误: private_key 不能为空")
+	if signerType(cfg.Signer.Type) == contracts.SignerPrivateKey && cfg.PrivateKey == "" {
+		return nil, fmt.Errorf("应急响应配置错误: 未配置 signer 时 private_key 不能为空")
 	}
 	if cfg.SafeAddress == "" {
 		return nil, fmt.Errorf("应急响应配置错误: safe_address 不能为空")
@@ -42,144 +67,352 @@ func NewManager(cfg *config.EmergencyConfig, inkRPC string, logger *zap.Logger)
 	if cfg.ArgusAddress == "" {
 		return nil, fmt.Errorf("应急响应配置错误: argus_address 不能为空")
 	}
-	if cfg.WithdrawAmount == "" {
-		return nil, fmt.Errorf("应急响应配置错误: withdraw_amount 不能为空")
+
+	// 创建签名器，生产环境使用 keystore 或远程签名，私钥不出现在配置中
+	signer, err := contracts.NewSigner(ctx, cfg.Signer, cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("创建签名器失败: %w", err)
+	}
+	if signerType(cfg.Signer.Type) == contracts.SignerPrivateKey {
+		logger.Warn("使用配置中的明文私钥签名，生产环境请配置 emergency.signer")
 	}
 
-	// 创建 Delegate
-	delegate := contracts.NewDelegate(
+	// 创建 Delegate，校验 chain ID 和委托人授权
+	var chainID uint64
+	switch {
+	case cfg.ChainID == 0:
+		chainID = contracts.InkChainID
+	case cfg.ChainID > 0:
+		chainID = uint64(cfg.ChainID)
+	}
+	delegate, err := contracts.NewDelegate(
+		ctx,
 		inkRPC,
-		cfg.PrivateKey,
+		signer,
 		cfg.SafeAddress,
 		cfg.ArgusAddress,
+		chainID,
+		logger,
 	)
+	if err != nil {
+		signer.Close()
+		return nil, fmt.Errorf("创建委托人失败: %w", err)
+	}
+
+	// 创建应急动作并校验规则引用
+	actions, err := NewActions(cfg, delegate, logger)
+	if err != nil {
+		delegate.Close()
+		return nil, fmt.Errorf("应急响应配置错误: %w", err)
+	}
+	if err := checkRuleActions(rules, actions); err != nil {
+		delegate.Close()
+		return nil, fmt.Errorf("应急响应配置错误: %w", err)
+	}
+	if err := checkChainRules(rules, actions); err != nil {
+		delegate.Close()
+		return nil, fmt.Errorf("应急响应配置错误: %w", err)
+	}
+
+	// 加载持久化的触发状态，重启后已触发的规则保持触发，需要显式重新布防
+	// 模拟模式的触发状态只保存在内存中，切换到正式模式时不会继承
+	stateFile := cfg.StateFile
+	if stateFile == "" {
+		stateFile = DefaultStateFile
+	}
+	if cfg.DryRun {
+		stateFile = ""
+	}
+	state, err := LoadStateStore(stateFile)
+	if err != nil {
+		delegate.Close()
+		return nil, fmt.Errorf("应急响应配置错误: %w", err)
+	}
+	for _, st := range state.Snapshot() {
+		if st.Triggered {
+			logger.Warn("应急动作处于已触发状态，需要重新布防后才会再次执行",
+				zap.String("rule", st.Rule),
+				zap.String("action", st.Action),
+				zap.Time("last_trigger", st.LastTrigger),
+			)
+		}
+	}
+
+	if cfg.DryRun {
+		logger.Warn("应急响应处于模拟模式，应急交易只模拟不广播")
+	}
 
 	logger.Info("应急响应管理器已启用",
 		zap.String("safe_address", cfg.SafeAddress),
 		zap.String("argus_address", cfg.ArgusAddress),
-		zap.String("withdraw_amount", cfg.WithdrawAmount),
+		zap.String("bot_address", delegate.Bot().Hex()),
+		zap.String("signer", signerType(cfg.Signer.Type)),
+		zap.Int("rules", len(rules.Rules())),
+		zap.Int("actions", len(actions)),
+		zap.String("state_file", stateFile),
+		zap.Bool("dry_run", cfg.DryRun),
 	)
 
 	return &Manager{
 		cfg:      cfg,
 		logger:   logger,
 		delegate: delegate,
+		rules:    rules,
+		actions:  actions,
+		state:    state,
+		metrics:  metrics,
 	}, nil
 }
 
-// CheckAlert 检查是否触发告警并执行应急响应
-func (m *Manager) CheckAlert(metricName string, value float64) error {
+// CheckAlert 使用告警规则检查指标值，命中时执行应急响应
+// ctx 用于应急交易的发送和回执等待，取消时停止等待
+func (m *Manager) CheckAlert(ctx context.Context, metricName string, value float64) error {
+	matches := m.rules.Evaluate(metricName, value)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	for _, match := range matches {
+		m.logger.Warn("告警规则命中",
+			zap.String("rule", match.Rule.Name),
+			zap.String("metric", match.Metric),
+			zap.Float64("value", match.Value),
+			zap.Int("count", match.Count),
+		)
+	}
+
 	if !m.cfg.Enabled {
 		return nil
 	}
 
-	// 判断是否触发告警
-	shouldTrigger := false
-	alertReason := ""
+	return m.executeActions(ctx, matches)
+}
 
-	switch metricName {
-	case "ink_eth_monitor_superchain_paused":
-		if value == 1.0 {
-			shouldTrigger = true
-			alertReason = "SuperChain 合约已暂停"
-		}
-	case "ink_eth_monitor_optimism_portal_paused":
-		if value == 1.0 {
-			shouldTrigger = true
-			alertReason = "Optimism Portal 合约已暂停"
+// executeActions 依次执行命中规则关联的应急动作
+// 每个 规则/动作 触发后保持触发状态直到重新布防；交易类动作被任一规则触发后其他规则也不会再次执行；
+// 动作在冷却时间内不会被任何规则再次执行。执行期间（包括提价重发和等待回执）不持有锁，
+// 其他检查和手动触发不会被阻塞，落盘的触发状态保证同一动作不会被重复执行
+func (m *Manager) executeActions(ctx context.Context, matches []Match) error {
+	var errs []error
+	executed := make(map[string]bool)
+	for _, match := range matches {
+		reason := match.Reason()
+		if until, ok := m.state.DisarmedUntil(match.Rule.Name, time.Now()); ok {
+			m.logger.Warn("应急规则已临时解除布防，跳过应急动作",
+				zap.String("rule", match.Rule.Name),
+				zap.String("reason", reason),
+				zap.Time("until", until),
+			)
+			for _, name := range match.Rule.Actions {
+				m.countAction(name, OutcomeDisarmed)
+			}
+			continue
 		}
-	case "ink_eth_monitor_standard_bridge_paused":
-		if value == 1.0 {
-			shouldTrigger = true
-			alertReason = "Standard Bridge 合约已暂停"
-		}
-	case "ink_eth_monitor_tydro_pool_paused":
-		if value == 1.0 {
-			shouldTrigger = true
-			alertReason = "Tydro Pool 合约已暂停"
-		}
-	case "ink_eth_monitor_oracle_price_spread":
-		if value > 0.05 {
-			shouldTrigger = true
-			alertReason = fmt.Sprintf("价格偏差过大: %.2f%% (超过5%%)", value*100)
-		}
-	case "ink_eth_monitor_remaining_supply":
-		if value < 2500 {
-			shouldTrigger = true
-			alertReason = fmt.Sprintf("剩余容量不足: %.2f tokens (低于2500)", value)
-		}
-	}
+		for _, name := range match.Rule.Actions {
+			if executed[name] {
+				continue
+			}
+			action := m.actions[name]
+
+			now, claimed, err := m.claimAction(match.Rule.Name, action, reason)
+			if err != nil {
+				m.logger.Error("保存应急触发状态失败，放弃执行", zap.String("action", name), zap.Error(err))
+				errs = append(errs, fmt.Errorf("应急动作 %s 未执行: %w", name, err))
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			executed[name] = true
+			m.countTrigger(match.Rule.Name, name)
 
-	if shouldTrigger {
-		return m.executeEmergencyWithdraw(alertReason)
+			m.logger.Warn("🚨 触发应急响应！开始执行应急动作...",
+				zap.String("reason", reason),
+				zap.String("rule", match.Rule.Name),
+				zap.String("action", action.Name()),
+				zap.String("type", action.Type()),
+			)
+
+			result, err := action.Execute(ctx, reason)
+			m.mu.Lock()
+			m.recordResult(match.Rule.Name, name, result, err)
+			m.mu.Unlock()
+			if err != nil {
+				m.logger.Error("应急动作执行失败", zap.String("action", name), zap.Error(err))
+				errs = append(errs, fmt.Errorf("应急动作 %s 执行失败: %w", name, err))
+				continue
+			}
+
+			m.logger.Info("✅ 应急动作执行成功",
+				zap.String("reason", reason),
+				zap.String("rule", match.Rule.Name),
+				zap.String("action", name),
+				zap.Time("trigger_time", now),
+			)
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// executeEmergencyWithdraw 执行应急提款
-func (m *Manager) executeEmergencyWithdraw(reason string) error {
+// claimAction 检查 规则/动作 是否可以执行，可以执行时先将触发状态落盘，返回触发时间
+// 检查和落盘在锁内完成，进程在执行中崩溃重启或其他检查并发命中时都不会重复执行
+func (m *Manager) claimAction(rule string, action Action, reason string) (time.Time, bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	name := action.Name()
 	// 检查是否已经触发过（防止重复执行）
-	if m.triggered {
-		m.logger.Warn("应急响应已触发过，跳过本次执行",
-			zap.String("reason", reason),
-			zap.Time("last_trigger_time", m.lastTriggerTime),
+	if m.state.IsTriggered(rule, name) {
+		m.logger.Debug("应急动作已触发过，等待重新布防",
+			zap.String("rule", rule),
+			zap.String("action", name),
 		)
-		return nil
+		return time.Time{}, false, nil
+	}
+	// 交易类动作按动作锁定：任一规则触发后（包括结果未知的交易），其他规则也不会再次发送
+	if action.Type() != ActionNotify {
+		if triggeredBy, ok := m.state.TriggeredBy(name); ok {
+			m.logger.Warn("应急动作已由其他规则触发，等待重新布防",
+				zap.String("rule", rule),
+				zap.String("action", name),
+				zap.String("triggered_by", triggeredBy),
+			)
+			return time.Time{}, false, nil
+		}
+	}
+	now := time.Now()
+	if remaining := m.state.CooldownRemaining(name, action.Cooldown(), now); remaining > 0 {
+		m.logger.Warn("应急动作处于冷却期，跳过本次执行",
+			zap.String("rule", rule),
+			zap.String("action", name),
+			zap.Duration("remaining", remaining),
+		)
+		m.countAction(name, OutcomeCooldown)
+		return time.Time{}, false, nil
 	}
 
-	m.logger.Warn("🚨 触发应急响应！开始执行提款操作...",
-		zap.String("reason", reason),
-		zap.String("withdraw_amount", m.cfg.WithdrawAmount),
-	)
+	if err := m.state.MarkTriggered(rule, name, reason, now); err != nil {
+		return time.Time{}, false, err
+	}
+	return now, true, nil
+}
 
-	// 解析提款金额
-	amount, ok := new(big.Int).SetString(m.cfg.WithdrawAmount, 10)
-	if !ok {
-		return fmt.Errorf("无法解析提款金额: %s", m.cfg.WithdrawAmount)
+// recordResult 将动作的最终结果写入触发状态和指标
+// 交易超时仍未上链时结果未知，保持触发状态避免重复发送，需要人工确认后重新布防；
+// 其他失败解除触发状态，允许下次告警重试
+func (m *Manager) recordResult(rule, action string, result *contracts.TxResult, execErr error) {
+	status := ""
+	if result != nil {
+		status = result.Status
+		txHash := ""
+		if len(result.Hashes) > 0 {
+			txHash = result.Hash.Hex()
+		}
+		if saveErr := m.state.RecordTx(rule, action, txHash, status, execErr); saveErr != nil {
+			m.logger.Error("保存应急交易结果失败", zap.String("action", action), zap.Error(saveErr))
+		}
+	} else if execErr != nil {
+		status = contracts.TxStatusFailed
+	}
+	if status != "" && m.metrics != nil {
+		m.metrics.IncEmergencyTx(action, status)
 	}
 
-	// 执行提款
-	err := m.delegate.WithdrawETHFromGatewayV3(amount)
-	if err != nil {
-		m.logger.Error("应急提款失败", zap.Error(err))
-		return fmt.Errorf("应急提款失败: %w", err)
+	if execErr == nil {
+		m.countAction(action, OutcomeSuccess)
+		return
 	}
+	if status == contracts.TxStatusPending {
+		m.countAction(action, OutcomePending)
+		m.logger.Error("应急交易结果未知，保持触发状态，请确认交易结果后手动重新布防",
+			zap.String("rule", rule),
+			zap.String("action", action),
+		)
+		return
+	}
+	m.countAction(action, OutcomeFailed)
+	if saveErr := m.state.MarkFailed(rule, action, execErr); saveErr != nil {
+		m.logger.Error("保存应急触发状态失败", zap.String("action", action), zap.Error(saveErr))
+	}
+}
 
-	// 标记已触发
-	m.triggered = true
-	m.lastTriggerTime = time.Now()
+// countTrigger 记录一次应急动作触发
+func (m *Manager) countTrigger(rule, action string) {
+	if m.metrics != nil {
+		m.metrics.IncEmergencyTrigger(rule, action)
+	}
+}
 
-	m.logger.Info("✅ 应急提款执行成功",
-		zap.String("reason", reason),
-		zap.String("amount", m.cfg.WithdrawAmount),
-		zap.Time("trigger_time", m.lastTriggerTime),
-	)
+// countAction 记录一次应急动作的执行结果
+func (m *Manager) countAction(action, outcome string) {
+	if m.metrics != nil {
+		m.metrics.IncEmergencyAction(action, outcome)
+	}
+}
 
-	return nil
+// Guards 检查指标是否关联了应急动作（应急响应启用且有规则匹配该指标）
+func (m *Manager) Guards(metricName string) bool {
+	return m.cfg.Enabled && m.rules.Covers(metricName)
 }
 
-// IsTriggered 检查是否已触发
+// IsTriggered 检查是否存在已触发的规则
 func (m *Manager) IsTriggered() bool {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.triggered
+	return m.state.AnyTriggered()
 }
 
-// Reset 重置触发状态（用于测试或手动恢复）
-func (m *Manager) Reset() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.triggered = false
-	m.lastTriggerTime = time.Time{}
-	m.logger.Info("应急响应状态已重置")
+// State 返回所有 规则/动作 的触发状态
+func (m *Manager) State() []TriggerState {
+	return m.state.Snapshot()
+}
+
+// Rearm 重新布防指定规则，使其关联的动作可以再次执行
+// rule 为 ManualRule 时重新布防手动触发的动作
+func (m *Manager) Rearm(rule string) error {
+	if rule != ManualRule && !m.hasRule(rule) {
+		return fmt.Errorf("规则不存在: %s", rule)
+	}
+	count, err := m.state.Rearm(rule)
+	if err != nil {
+		return fmt.Errorf("重新布防失败: %w", err)
+	}
+	m.logger.Info("应急规则已重新布防", zap.String("rule", rule), zap.Int("actions", count))
+	return nil
+}
+
+// Reset 重新布防所有规则（用于测试或手动恢复）
+func (m *Manager) Reset() error {
+	count, err := m.state.Rearm("")
+	if err != nil {
+		return fmt.Errorf("重置应急响应状态失败: %w", err)
+	}
+	m.logger.Info("应急响应状态已重置", zap.Int("actions", count))
+	return nil
+}
+
+// signerType 返回签名器类型，未配置时为 private_key
+func signerType(typeName string) string {
+	if typeName == "" {
+		return contracts.SignerPrivateKey
+	}
+	return typeName
+}
+
+// hasRule 检查规则是否存在
+func (m *Manager) hasRule(name string) bool {
+	for _, rule := range m.rules.Rules() {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // Close 关闭应急响应管理器
 func (m *Manager) Close() error {
 	m.logger.Info("关闭应急响应管理器")
+	if m.delegate != nil {
+		m.delegate.Close()
+	}
 	return nil
 }
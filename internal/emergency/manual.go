@@ -0,0 +1,171 @@
+package emergency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/contracts"
+)
+
+// ManualRule 手动触发的动作在触发状态中记录的规则名称
+const ManualRule = "manual"
+
+// 手动操作的限制
+const (
+	MaxDisarmDuration = 24 * time.Hour   // 单次解除布防的最长时间
+	PreviewValidity   = 10 * time.Minute // 预览后允许手动触发的时间
+)
+
+// Preview 动作的预览结果
+type Preview struct {
+	Action     string                      `json:"action"`
+	Type       string                      `json:"type"`
+	Simulation *contracts.SimulationResult `json:"simulation,omitempty"`  // 交易类动作的模拟结果
+	Cooldown   time.Duration               `json:"cooldown_remaining"`    // 剩余冷却时间，大于0时无法手动触发
+	ValidUntil *time.Time                  `json:"valid_until,omitempty"` // 在此之前可以手动触发，模拟失败时为空
+}
+
+// Disarm 临时解除规则的布防，期间规则命中只记录日志不执行动作，到期后自动恢复
+// rule 为空时解除全部规则，返回恢复时间
+func (m *Manager) Disarm(rule string, d time.Duration) (time.Time, error) {
+	if d <= 0 || d > MaxDisarmDuration {
+		return time.Time{}, fmt.Errorf("解除布防时间需在 0 到 %s 之间", MaxDisarmDuration)
+	}
+	key := rule
+	if rule == "" {
+		key = DisarmAll
+	} else if !m.hasRule(rule) {
+		return time.Time{}, fmt.Errorf("规则不存在: %s", rule)
+	}
+
+	until := time.Now().Add(d)
+	if err := m.state.Disarm(key, until); err != nil {
+		return time.Time{}, fmt.Errorf("解除布防失败: %w", err)
+	}
+	m.logger.Warn("应急规则已临时解除布防", zap.String("rule", key), zap.Time("until", until))
+	return until, nil
+}
+
+// Arm 恢复临时解除布防的规则，rule 为空时恢复全部
+func (m *Manager) Arm(rule string) error {
+	key := rule
+	if rule == "" {
+		key = DisarmAll
+	}
+	count, err := m.state.Arm(key)
+	if err != nil {
+		return fmt.Errorf("恢复布防失败: %w", err)
+	}
+	m.logger.Info("应急规则已恢复布防", zap.String("rule", key), zap.Int("rules", count))
+	return nil
+}
+
+// Disarmed 返回尚未到期的解除布防及恢复时间，* 表示全部规则
+func (m *Manager) Disarmed() map[string]time.Time {
+	return m.state.Disarmed(time.Now())
+}
+
+// Preview 模拟执行动作但不广播，预览成功后 PreviewValidity 内可以手动触发该动作
+func (m *Manager) Preview(ctx context.Context, name string) (*Preview, error) {
+	action, ok := m.actions[name]
+	if !ok {
+		return nil, fmt.Errorf("应急动作不存在: %s", name)
+	}
+
+	simulation, err := action.Preview(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	preview := &Preview{
+		Action:     name,
+		Type:       action.Type(),
+		Simulation: simulation,
+		Cooldown:   m.state.CooldownRemaining(name, action.Cooldown(), now),
+	}
+	if simulation != nil && !simulation.Success {
+		return preview, nil
+	}
+
+	m.mu.Lock()
+	if m.previews == nil {
+		m.previews = make(map[string]time.Time)
+	}
+	m.previews[name] = now
+	m.mu.Unlock()
+	validUntil := now.Add(PreviewValidity)
+	preview.ValidUntil = &validUntil
+
+	m.logger.Info("预览应急动作", zap.String("action", name), zap.Duration("cooldown_remaining", preview.Cooldown))
+	return preview, nil
+}
+
+// Trigger 手动执行应急动作，需要先在 PreviewValidity 内预览成功，每次预览只能触发一次
+// 动作的冷却时间同样生效；执行后在触发状态中记录为 ManualRule，再次手动触发前需要重新布防 ManualRule；
+// 交易类动作已由任一规则触发（包括结果未知的交易）时同样拒绝，需要确认交易结果并重新布防该规则
+func (m *Manager) Trigger(ctx context.Context, name, reason string) (*contracts.TxResult, error) {
+	if !m.cfg.Enabled {
+		return nil, fmt.Errorf("应急响应功能未启用")
+	}
+	action, ok := m.actions[name]
+	if !ok {
+		return nil, fmt.Errorf("应急动作不存在: %s", name)
+	}
+
+	reason = "手动触发: " + reason
+	if err := m.claimManual(action, reason); err != nil {
+		return nil, err
+	}
+	m.countTrigger(ManualRule, name)
+
+	m.logger.Warn("🚨 手动触发应急动作",
+		zap.String("reason", reason),
+		zap.String("action", name),
+		zap.String("type", action.Type()),
+	)
+	// 执行期间不持有锁，落盘的触发状态保证不会被重复执行
+	result, err := action.Execute(ctx, reason)
+	m.mu.Lock()
+	m.recordResult(ManualRule, name, result, err)
+	m.mu.Unlock()
+	if err != nil {
+		m.logger.Error("应急动作执行失败", zap.String("action", name), zap.Error(err))
+		return result, fmt.Errorf("应急动作 %s 执行失败: %w", name, err)
+	}
+	m.logger.Info("✅ 应急动作执行成功", zap.String("reason", reason), zap.String("action", name))
+	return result, nil
+}
+
+// claimManual 检查手动触发的前提条件（触发状态、预览和冷却时间），满足时消耗预览并将触发状态落盘
+func (m *Manager) claimManual(action Action, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := action.Name()
+	if m.state.IsTriggered(ManualRule, name) {
+		return fmt.Errorf("应急动作 %s 已手动触发，需要先重新布防 %s 规则", name, ManualRule)
+	}
+	if action.Type() != ActionNotify {
+		if rule, ok := m.state.TriggeredBy(name); ok {
+			return fmt.Errorf("应急动作 %s 已由规则 %s 触发（交易可能仍未确认），请确认交易结果并重新布防后再手动触发", name, rule)
+		}
+	}
+
+	now := time.Now()
+	previewed, ok := m.previews[name]
+	if !ok || now.Sub(previewed) > PreviewValidity {
+		return fmt.Errorf("应急动作 %s 需要先预览，预览后 %s 内有效", name, PreviewValidity)
+	}
+	if remaining := m.state.CooldownRemaining(name, action.Cooldown(), now); remaining > 0 {
+		return fmt.Errorf("应急动作 %s 处于冷却期，剩余 %s", name, remaining.Truncate(time.Second))
+	}
+	delete(m.previews, name)
+
+	if err := m.state.MarkTriggered(ManualRule, name, reason, now); err != nil {
+		return fmt.Errorf("应急动作 %s 未执行: %w", name, err)
+	}
+	return nil
+}
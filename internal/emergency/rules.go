@@ -0,0 +1,231 @@
+package emergency
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sync"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+)
+
+// 规则比较运算符
+const (
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+	OpChange       = "change" // 与上一次的值相比变化量（绝对值）超过阈值
+)
+
+// DefaultRules 未配置规则时使用的默认规则（与早期硬编码的告警条件一致）
+var DefaultRules = []config.AlertRuleConfig{
+	{Name: "superchain_paused", Metric: "ink_eth_monitor_superchain_paused", Operator: OpEqual, Threshold: 1},
+	{Name: "optimism_portal_paused", Metric: "ink_eth_monitor_optimism_portal_paused", Operator: OpEqual, Threshold: 1},
+	{Name: "standard_bridge_paused", Metric: "ink_eth_monitor_standard_bridge_paused", Operator: OpEqual, Threshold: 1},
+	{Name: "tydro_pool_paused", Metric: "ink_eth_monitor_tydro_pool_paused", Operator: OpEqual, Threshold: 1},
+	{Name: "oracle_price_spread", Metric: "ink_eth_monitor_oracle_price_spread", Operator: OpGreater, Threshold: 0.05},
+	{Name: "remaining_supply", Metric: "ink_eth_monitor_remaining_supply", Operator: OpLess, Threshold: 2500},
+}
+
+// Rule 告警规则
+type Rule struct {
+	Name      string
+	Metric    string // 指标名称，支持 path.Match 通配符
+	Operator  string
+	Threshold float64
+	For       int      // 连续满足条件的轮询次数
+	Actions   []string // 命中后执行的应急动作
+}
+
+// Match 规则命中结果
+type Match struct {
+	Rule   *Rule
+	Metric string
+	Value  float64
+	Count  int // 已连续满足条件的次数
+}
+
+// Reason 返回命中原因描述
+func (m Match) Reason() string {
+	return fmt.Sprintf("规则 %s 命中: %s %s %v (当前值 %v, 连续 %d 次)",
+		m.Rule.Name, m.Metric, m.Rule.Operator, m.Rule.Threshold, m.Value, m.Count)
+}
+
+// ruleState 规则在单个指标上的评估状态
+type ruleState struct {
+	prev    float64
+	hasPrev bool
+	count   int
+}
+
+// RuleEngine 告警规则引擎
+type RuleEngine struct {
+	rules  []*Rule
+	states map[string]*ruleState // 按 规则名/指标名 索引
+	mu     sync.Mutex
+}
+
+// NewRuleEngine 根据配置创建规则引擎，未配置规则时使用 DefaultRules
+func NewRuleEngine(cfgs []config.AlertRuleConfig) (*RuleEngine, error) {
+	if len(cfgs) == 0 {
+		cfgs = DefaultRules
+	}
+
+	names := make(map[string]bool)
+	rules := make([]*Rule, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		rule, err := newRule(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("emergency.rules[%d]: %w", i, err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("emergency.rules[%d]: 规则名称重复: %s", i, rule.Name)
+		}
+		names[rule.Name] = true
+		rules = append(rules, rule)
+	}
+
+	return &RuleEngine{
+		rules:  rules,
+		states: make(map[string]*ruleState),
+	}, nil
+}
+
+// newRule 校验配置并创建规则
+func newRule(cfg config.AlertRuleConfig) (*Rule, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("name 不能为空")
+	}
+	if cfg.Metric == "" {
+		return nil, fmt.Errorf("规则 %s 的 metric 不能为空", cfg.Name)
+	}
+	if _, err := path.Match(cfg.Metric, ""); err != nil {
+		return nil, fmt.Errorf("规则 %s 的 metric 格式错误: %w", cfg.Name, err)
+	}
+	switch cfg.Operator {
+	case OpGreater, OpGreaterEqual, OpLess, OpLessEqual, OpEqual, OpNotEqual, OpChange:
+	default:
+		return nil, fmt.Errorf("规则 %s 的 operator 不支持: %q", cfg.Name, cfg.Operator)
+	}
+	if cfg.For < 0 {
+		return nil, fmt.Errorf("规则 %s 的 for 不能为负数", cfg.Name)
+	}
+
+	forCount := cfg.For
+	if forCount == 0 {
+		forCount = 1
+	}
+
+	actions := cfg.Actions
+	if len(actions) == 0 {
+		actions = []string{DefaultActionName}
+	}
+
+	return &Rule{
+		Name:      cfg.Name,
+		Metric:    cfg.Metric,
+		Operator:  cfg.Operator,
+		Threshold: cfg.Threshold,
+		For:       forCount,
+		Actions:   actions,
+	}, nil
+}
+
+// Rules 返回所有规则
+func (e *RuleEngine) Rules() []*Rule {
+	return e.rules
+}
+
+// Matching 返回匹配指定指标的规则
+func (e *RuleEngine) Matching(metricName string) []*Rule {
+	var rules []*Rule
+	for _, rule := range e.rules {
+		if ok, _ := path.Match(rule.Metric, metricName); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Covers 检查是否有规则匹配指定指标
+func (e *RuleEngine) Covers(metricName string) bool {
+	for _, rule := range e.rules {
+		if ok, _ := path.Match(rule.Metric, metricName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate 使用新的指标值评估所有匹配的规则，返回连续满足次数达到 For 的规则
+func (e *RuleEngine) Evaluate(metricName string, value float64) []Match {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var matches []Match
+	for _, rule := range e.rules {
+		if ok, _ := path.Match(rule.Metric, metricName); !ok {
+			continue
+		}
+
+		key := rule.Name + "/" + metricName
+		state, exists := e.states[key]
+		if !exists {
+			state = &ruleState{}
+			e.states[key] = state
+		}
+
+		if rule.satisfied(state, value) {
+			state.count++
+		} else {
+			state.count = 0
+		}
+		state.prev = value
+		state.hasPrev = true
+
+		if state.count >= rule.For {
+			matches = append(matches, Match{
+				Rule:   rule,
+				Metric: metricName,
+				Value:  value,
+				Count:  state.count,
+			})
+		}
+	}
+	return matches
+}
+
+// satisfied 判断本次的值是否满足规则条件
+func (r *Rule) satisfied(state *ruleState, value float64) bool {
+	if math.IsNaN(value) {
+		return false
+	}
+
+	switch r.Operator {
+	case OpGreater:
+		return value > r.Threshold
+	case OpGreaterEqual:
+		return value >= r.Threshold
+	case OpLess:
+		return value < r.Threshold
+	case OpLessEqual:
+		return value <= r.Threshold
+	case OpEqual:
+		return value == r.Threshold
+	case OpNotEqual:
+		return value != r.Threshold
+	case OpChange:
+		if !state.hasPrev {
+			return false
+		}
+		delta := math.Abs(value - state.prev)
+		if r.Threshold == 0 {
+			return delta != 0
+		}
+		return delta > r.Threshold
+	}
+	return false
+}
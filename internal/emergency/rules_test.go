@@ -0,0 +1,110 @@
+package emergency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+)
+
+// TestRuleEngine_DefaultRules 测试未配置规则时使用默认规则
+func TestRuleEngine_DefaultRules(t *testing.T) {
+	engine, err := NewRuleEngine(nil)
+	require.NoError(t, err)
+	assert.Len(t, engine.Rules(), len(DefaultRules))
+
+	assert.Empty(t, engine.Evaluate("ink_eth_monitor_superchain_paused", 0))
+	assert.Len(t, engine.Evaluate("ink_eth_monitor_superchain_paused", 1), 1)
+	assert.Empty(t, engine.Evaluate("ink_eth_monitor_oracle_price_spread", 0.05))
+	assert.Len(t, engine.Evaluate("ink_eth_monitor_oracle_price_spread", 0.051), 1)
+	assert.Len(t, engine.Evaluate("ink_eth_monitor_remaining_supply", 2499), 1)
+	assert.Empty(t, engine.Evaluate("ink_eth_monitor_unknown", 1))
+}
+
+// TestRuleEngine_Operators 测试各比较运算符
+func TestRuleEngine_Operators(t *testing.T) {
+	tests := []struct {
+		operator string
+		value    float64
+		want     bool
+	}{
+		{OpGreater, 11, true},
+		{OpGreater, 10, false},
+		{OpGreaterEqual, 10, true},
+		{OpLess, 9, true},
+		{OpLess, 10, false},
+		{OpLessEqual, 10, true},
+		{OpEqual, 10, true},
+		{OpEqual, 10.5, false},
+		{OpNotEqual, 10.5, true},
+		{OpNotEqual, 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator, func(t *testing.T) {
+			engine, err := NewRuleEngine([]config.AlertRuleConfig{
+				{Name: "r", Metric: "m", Operator: tt.operator, Threshold: 10},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, len(engine.Evaluate("m", tt.value)) == 1)
+		})
+	}
+}
+
+// TestRuleEngine_For 测试连续满足次数
+func TestRuleEngine_For(t *testing.T) {
+	engine, err := NewRuleEngine([]config.AlertRuleConfig{
+		{Name: "spread", Metric: "ink_eth_monitor_*_spread", Operator: OpGreater, Threshold: 0.05, For: 3},
+	})
+	require.NoError(t, err)
+
+	metric := "ink_eth_monitor_oracle_spread"
+	assert.Empty(t, engine.Evaluate(metric, 0.1))
+	assert.Empty(t, engine.Evaluate(metric, 0.1))
+	assert.Empty(t, engine.Evaluate(metric, 0.01), "中断后重新计数")
+	assert.Empty(t, engine.Evaluate(metric, 0.1))
+	assert.Empty(t, engine.Evaluate(metric, 0.1))
+
+	matches := engine.Evaluate(metric, 0.1)
+	require.Len(t, matches, 1)
+	assert.Equal(t, 3, matches[0].Count)
+	assert.Equal(t, "spread", matches[0].Rule.Name)
+
+	// 不同指标分别计数
+	assert.Empty(t, engine.Evaluate("ink_eth_monitor_other_spread", 0.1))
+}
+
+// TestRuleEngine_Change 测试与上一次相比的变化
+func TestRuleEngine_Change(t *testing.T) {
+	engine, err := NewRuleEngine([]config.AlertRuleConfig{
+		{Name: "jump", Metric: "price", Operator: OpChange, Threshold: 100},
+		{Name: "any", Metric: "paused", Operator: OpChange},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, engine.Evaluate("price", 3000), "首次没有上一次的值")
+	assert.Empty(t, engine.Evaluate("price", 3050))
+	assert.Len(t, engine.Evaluate("price", 2900), 1)
+
+	assert.Empty(t, engine.Evaluate("paused", 0))
+	assert.Empty(t, engine.Evaluate("paused", 0))
+	assert.Len(t, engine.Evaluate("paused", 1), 1)
+}
+
+// TestNewRuleEngine_Invalid 测试非法规则配置
+func TestNewRuleEngine_Invalid(t *testing.T) {
+	invalid := [][]config.AlertRuleConfig{
+		{{Name: "", Metric: "m", Operator: OpGreater}},
+		{{Name: "r", Metric: "", Operator: OpGreater}},
+		{{Name: "r", Metric: "m", Operator: "=>"}},
+		{{Name: "r", Metric: "[", Operator: OpGreater}},
+		{{Name: "r", Metric: "m", Operator: OpGreater, For: -1}},
+		{{Name: "r", Metric: "m", Operator: OpGreater}, {Name: "r", Metric: "n", Operator: OpLess}},
+	}
+	for i, cfgs := range invalid {
+		_, err := NewRuleEngine(cfgs)
+		assert.Error(t, err, "case %d", i)
+	}
+}
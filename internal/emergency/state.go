@@ -0,0 +1,321 @@
+package emergency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultStateFile 默认的触发状态文件
+const DefaultStateFile = "data/emergency_state.json"
+
+// TriggerState 单个 规则/动作 的触发状态
+// Triggered 为 true 时该规则不会再次执行该动作，直到显式重新布防
+type TriggerState struct {
+	Rule        string    `json:"rule"`
+	Action      string    `json:"action"`
+	Triggered   bool      `json:"triggered"`
+	Count       int       `json:"count"`
+	LastReason  string    `json:"last_reason,omitempty"`
+	LastTrigger time.Time `json:"last_trigger"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastTxHash  string    `json:"last_tx_hash,omitempty"`
+	LastTxState string    `json:"last_tx_status,omitempty"` // 最近一笔交易的最终状态
+}
+
+// stateFile 状态文件格式
+type stateFile struct {
+	Triggers    []*TriggerState      `json:"triggers"`
+	ActionsLast map[string]time.Time `json:"actions_last"`       // 每个动作最近一次执行时间，用于冷却
+	Disarmed    map[string]time.Time `json:"disarmed,omitempty"` // 临时解除布防的规则及恢复时间，* 表示全部规则
+}
+
+// StateStore 应急触发状态存储
+// 每次状态变化都会写入文件，重启后保留已触发的状态，避免崩溃重启导致重复执行或静默重新布防
+type StateStore struct {
+	path        string
+	triggers    map[string]*TriggerState // 按 规则/动作 索引
+	actionsLast map[string]time.Time
+	prevLast    map[string]time.Time // 执行中动作的上一次执行时间，失败时恢复
+	disarmed    map[string]time.Time // 临时解除布防的规则及恢复时间
+	mu          sync.Mutex
+}
+
+// LoadStateStore 加载状态文件，文件不存在时创建空状态；path 为空时仅在内存中保存
+func LoadStateStore(path string) (*StateStore, error) {
+	s := &StateStore{
+		path:        path,
+		triggers:    make(map[string]*TriggerState),
+		actionsLast: make(map[string]time.Time),
+		prevLast:    make(map[string]time.Time),
+		disarmed:    make(map[string]time.Time),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取应急状态文件失败: %w", err)
+	}
+
+	var file stateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("解析应急状态文件失败: %w", err)
+	}
+	for _, st := range file.Triggers {
+		s.triggers[stateKey(st.Rule, st.Action)] = st
+	}
+	for action, t := range file.ActionsLast {
+		s.actionsLast[action] = t
+	}
+	for rule, until := range file.Disarmed {
+		s.disarmed[rule] = until
+	}
+	return s, nil
+}
+
+// stateKey 返回 规则/动作 索引
+func stateKey(rule, action string) string {
+	return rule + "/" + action
+}
+
+// IsTriggered 检查规则的动作是否已触发
+func (s *StateStore) IsTriggered(rule, action string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.triggers[stateKey(rule, action)]
+	return ok && st.Triggered
+}
+
+// TriggeredBy 返回动作处于触发状态（含结果未知）的任一规则
+func (s *StateStore) TriggeredBy(action string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range s.triggers {
+		if st.Action == action && st.Triggered {
+			return st.Rule, true
+		}
+	}
+	return "", false
+}
+
+// CooldownRemaining 返回动作剩余的冷却时间
+func (s *StateStore) CooldownRemaining(action string, cooldown time.Duration, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.actionsLast[action]
+	if !ok || cooldown <= 0 {
+		return 0
+	}
+	if remaining := last.Add(cooldown).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// MarkTriggered 在执行动作前标记已触发并落盘
+// 写入失败时返回错误，调用方不应继续执行动作
+func (s *StateStore) MarkTriggered(rule, action, reason string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := stateKey(rule, action)
+	st, ok := s.triggers[key]
+	if !ok {
+		st = &TriggerState{Rule: rule, Action: action}
+		s.triggers[key] = st
+	}
+	st.Triggered = true
+	st.Count++
+	st.LastReason = reason
+	st.LastTrigger = now
+	st.LastError = ""
+	st.LastTxHash = ""
+	st.LastTxState = ""
+	s.prevLast[action] = s.actionsLast[action]
+	s.actionsLast[action] = now
+
+	return s.saveLocked()
+}
+
+// MarkFailed 动作执行失败时解除触发状态，允许下次告警重试
+func (s *StateStore) MarkFailed(rule, action string, execErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.triggers[stateKey(rule, action)]
+	if !ok {
+		return nil
+	}
+	st.Triggered = false
+	st.LastError = execErr.Error()
+	if prev := s.prevLast[action]; prev.IsZero() {
+		delete(s.actionsLast, action)
+	} else {
+		s.actionsLast[action] = prev
+	}
+	delete(s.prevLast, action)
+
+	return s.saveLocked()
+}
+
+// RecordTx 记录动作交易的最终结果，不改变触发状态
+func (s *StateStore) RecordTx(rule, action, txHash, status string, execErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.triggers[stateKey(rule, action)]
+	if !ok {
+		return nil
+	}
+	st.LastTxHash = txHash
+	st.LastTxState = status
+	if execErr != nil {
+		st.LastError = execErr.Error()
+	}
+
+	return s.saveLocked()
+}
+
+// Rearm 重新布防指定规则的所有动作，rule 为空时重新布防全部，返回被重新布防的数量
+func (s *StateStore) Rearm(rule string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, st := range s.triggers {
+		if (rule == "" || st.Rule == rule) && st.Triggered {
+			st.Triggered = false
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return count, s.saveLocked()
+}
+
+// DisarmAll 临时解除布防时表示全部规则
+const DisarmAll = "*"
+
+// Disarm 临时解除规则的布防直到 until，rule 为 DisarmAll 时解除全部规则
+func (s *StateStore) Disarm(rule string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disarmed[rule] = until
+	return s.saveLocked()
+}
+
+// Arm 恢复临时解除布防的规则，rule 为 DisarmAll 时恢复全部，返回恢复的数量
+func (s *StateStore) Arm(rule string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for r := range s.disarmed {
+		if rule == DisarmAll || r == rule {
+			delete(s.disarmed, r)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return count, s.saveLocked()
+}
+
+// DisarmedUntil 返回规则解除布防的恢复时间，未解除或已到期时返回 false
+func (s *StateStore) DisarmedUntil(rule string, now time.Time) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var until time.Time
+	for _, r := range []string{rule, DisarmAll} {
+		if t, ok := s.disarmed[r]; ok && t.After(now) && t.After(until) {
+			until = t
+		}
+	}
+	return until, !until.IsZero()
+}
+
+// Disarmed 返回尚未到期的解除布防，按规则索引
+func (s *StateStore) Disarmed(now time.Time) map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	disarmed := make(map[string]time.Time)
+	for rule, until := range s.disarmed {
+		if until.After(now) {
+			disarmed[rule] = until
+		}
+	}
+	return disarmed
+}
+
+// AnyTriggered 检查是否存在已触发的状态
+func (s *StateStore) AnyTriggered() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range s.triggers {
+		if st.Triggered {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot 返回所有触发状态的副本，按规则和动作排序
+func (s *StateStore) Snapshot() []TriggerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]TriggerState, 0, len(s.triggers))
+	for _, st := range s.triggers {
+		states = append(states, *st)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return stateKey(states[i].Rule, states[i].Action) < stateKey(states[j].Rule, states[j].Action)
+	})
+	return states
+}
+
+// saveLocked 原子写入状态文件（先写临时文件再重命名），调用方需持有锁
+func (s *StateStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	file := stateFile{ActionsLast: s.actionsLast, Disarmed: s.disarmed}
+	for _, st := range s.triggers {
+		file.Triggers = append(file.Triggers, st)
+	}
+	sort.Slice(file.Triggers, func(i, j int) bool {
+		return stateKey(file.Triggers[i].Rule, file.Triggers[i].Action) < stateKey(file.Triggers[j].Rule, file.Triggers[j].Action)
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化应急状态失败: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("创建应急状态目录失败: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("写入应急状态文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("写入应急状态文件失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package emergency
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// fakeAction 记录执行次数的测试动作
+type fakeAction struct {
+	name     string
+	cooldown time.Duration
+	err      error
+	result   *contracts.TxResult
+	calls    int
+	block    chan struct{} // 不为 nil 时执行阻塞到关闭，模拟等待交易回执
+}
+
+func (a *fakeAction) Name() string            { return a.name }
+func (a *fakeAction) Type() string            { return "fake" }
+func (a *fakeAction) Cooldown() time.Duration { return a.cooldown }
+
+func (a *fakeAction) Execute(ctx context.Context, reason string) (*contracts.TxResult, error) {
+	a.calls++
+	if a.block != nil {
+		<-a.block
+	}
+	return a.result, a.err
+}
+
+func (a *fakeAction) Preview(ctx context.Context) (*contracts.SimulationResult, error) {
+	return nil, nil
+}
+
+// newTestManager 创建使用测试动作的管理器
+func newTestManager(t *testing.T, stateFile string, rules []config.AlertRuleConfig, actions ...*fakeAction) *Manager {
+	engine, err := NewRuleEngine(rules)
+	require.NoError(t, err)
+	state, err := LoadStateStore(stateFile)
+	require.NoError(t, err)
+
+	m := &Manager{
+		cfg:     &config.EmergencyConfig{Enabled: true},
+		logger:  zap.NewNop(),
+		rules:   engine,
+		actions: make(map[string]Action),
+		state:   state,
+	}
+	for _, action := range actions {
+		m.actions[action.name] = action
+	}
+	return m
+}
+
+// TestManager_PerRuleLatch 测试触发状态按规则独立保存
+func TestManager_PerRuleLatch(t *testing.T) {
+	withdraw := &fakeAction{name: "withdraw"}
+	notify := &fakeAction{name: "notify"}
+	m := newTestManager(t, "", []config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+		{Name: "spread", Metric: "spread", Operator: OpGreater, Threshold: 0.05, Actions: []string{"notify"}},
+	}, withdraw, notify)
+
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 1, withdraw.calls, "已触发的规则不会重复执行")
+	assert.True(t, m.IsTriggered())
+
+	// 其他规则不受影响
+	require.NoError(t, m.CheckAlert(context.Background(), "spread", 0.1))
+	assert.Equal(t, 1, notify.calls)
+
+	// 重新布防后可以再次执行
+	require.NoError(t, m.Rearm("paused"))
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 2, withdraw.calls)
+
+	assert.Error(t, m.Rearm("unknown"))
+}
+
+// TestManager_Cooldown 测试动作冷却时间跨规则生效
+func TestManager_Cooldown(t *testing.T) {
+	withdraw := &fakeAction{name: "withdraw", cooldown: time.Hour}
+	m := newTestManager(t, "", []config.AlertRuleConfig{
+		{Name: "a", Metric: "a", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+		{Name: "b", Metric: "b", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+	}, withdraw)
+
+	require.NoError(t, m.CheckAlert(context.Background(), "a", 1))
+	require.NoError(t, m.CheckAlert(context.Background(), "b", 1))
+	assert.Equal(t, 1, withdraw.calls, "冷却期内其他规则不会再次执行")
+}
+
+// TestManager_ActionLatch 测试交易类动作被任一规则触发后，其他规则不会再次执行
+func TestManager_ActionLatch(t *testing.T) {
+	withdraw := &fakeAction{name: "withdraw"}
+	m := newTestManager(t, "", []config.AlertRuleConfig{
+		{Name: "a", Metric: "a", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+		{Name: "b", Metric: "b", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+	}, withdraw)
+
+	var wg sync.WaitGroup
+	for _, metric := range []string{"a", "b", "a", "b"} {
+		wg.Add(1)
+		go func(metric string) {
+			defer wg.Done()
+			assert.NoError(t, m.CheckAlert(context.Background(), metric, 1))
+		}(metric)
+	}
+	wg.Wait()
+	assert.Equal(t, 1, withdraw.calls, "并发命中的多条规则只执行一次")
+
+	// 结果未知的交易同样锁定该动作
+	require.NoError(t, m.Reset())
+	withdraw.result = &contracts.TxResult{Status: contracts.TxStatusPending}
+	withdraw.err = errors.New("timeout")
+	assert.Error(t, m.CheckAlert(context.Background(), "a", 1))
+	require.NoError(t, m.CheckAlert(context.Background(), "b", 1))
+	assert.Equal(t, 2, withdraw.calls)
+
+	// 重新布防触发的规则后，其他规则可以执行
+	withdraw.result, withdraw.err = nil, nil
+	require.NoError(t, m.Rearm("a"))
+	require.NoError(t, m.CheckAlert(context.Background(), "b", 1))
+	assert.Equal(t, 3, withdraw.calls)
+}
+
+// TestManager_FailedActionRetries 测试执行失败后解除触发状态
+func TestManager_FailedActionRetries(t *testing.T) {
+	withdraw := &fakeAction{name: "withdraw", err: errors.New("rpc down")}
+	m := newTestManager(t, "", []config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+	}, withdraw)
+
+	assert.Error(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.False(t, m.IsTriggered())
+
+	withdraw.err = nil
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 2, withdraw.calls)
+
+	states := m.State()
+	require.Len(t, states, 1)
+	assert.Equal(t, 2, states[0].Count)
+	assert.Empty(t, states[0].LastError)
+}
+
+// TestManager_ExecuteWithoutLock 测试动作执行期间其他规则和手动触发不被阻塞，同一动作不会重复执行
+func TestManager_ExecuteWithoutLock(t *testing.T) {
+	withdraw := &fakeAction{name: "withdraw", block: make(chan struct{})}
+	notify := &fakeAction{name: "notify"}
+	m := newTestManager(t, "", []config.AlertRuleConfig{
+		{Name: "a", Metric: "a", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+		{Name: "b", Metric: "b", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+		{Name: "spread", Metric: "spread", Operator: OpGreater, Threshold: 0.05, Actions: []string{"notify"}},
+	}, withdraw, notify)
+
+	done := make(chan error)
+	go func() { done <- m.CheckAlert(context.Background(), "a", 1) }()
+	require.Eventually(t, func() bool { return m.state.IsTriggered("a", "withdraw") }, time.Second, 10*time.Millisecond)
+
+	// withdraw 等待回执期间
+	require.NoError(t, m.CheckAlert(context.Background(), "spread", 0.1))
+	assert.Equal(t, 1, notify.calls, "其他规则的动作不被阻塞")
+	require.NoError(t, m.CheckAlert(context.Background(), "b", 1))
+	_, err := m.Trigger(context.Background(), "withdraw", "test")
+	assert.ErrorContains(t, err, "已由规则 a 触发", "手动触发立即返回")
+
+	close(withdraw.block)
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, withdraw.calls, "执行中的动作不会被其他规则重复执行")
+}
+
+// TestManager_TxOutcome 测试交易结果写入触发状态
+func TestManager_TxOutcome(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	withdraw := &fakeAction{
+		name:   "withdraw",
+		err:    errors.New("execution reverted"),
+		result: &contracts.TxResult{Hash: hash, Hashes: []common.Hash{hash}, Status: contracts.TxStatusReverted},
+	}
+	m := newTestManager(t, "", []config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+	}, withdraw)
+
+	// 交易执行失败：解除触发状态，允许重试
+	assert.Error(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.False(t, m.IsTriggered())
+	states := m.State()
+	require.Len(t, states, 1)
+	assert.Equal(t, hash.Hex(), states[0].LastTxHash)
+	assert.Equal(t, contracts.TxStatusReverted, states[0].LastTxState)
+
+	// 交易超时结果未知：保持触发状态，避免重复发送
+	withdraw.err = errors.New("timeout")
+	withdraw.result.Status = contracts.TxStatusPending
+	assert.Error(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.True(t, m.IsTriggered())
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 2, withdraw.calls)
+	assert.Equal(t, contracts.TxStatusPending, m.State()[0].LastTxState)
+	assert.Equal(t, "timeout", m.State()[0].LastError)
+}
+
+// TestStateStore_SurvivesRestart 测试触发状态在重启后保留
+func TestStateStore_SurvivesRestart(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state", "emergency.json")
+	rules := []config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+	}
+
+	first := &fakeAction{name: "withdraw"}
+	m := newTestManager(t, stateFile, rules, first)
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 1, first.calls)
+
+	// 模拟重启：不会静默重新布防
+	second := &fakeAction{name: "withdraw"}
+	m = newTestManager(t, stateFile, rules, second)
+	assert.True(t, m.IsTriggered())
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 0, second.calls)
+
+	// 重新布防后状态同样持久化
+	require.NoError(t, m.Reset())
+	m = newTestManager(t, stateFile, rules, second)
+	assert.False(t, m.IsTriggered())
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 1, second.calls)
+}
+
+// TestManager_Disarm 测试临时解除布防期间规则命中不执行动作，恢复布防后正常执行
+func TestManager_Disarm(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "emergency.json")
+	rules := []config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+	}
+	withdraw := &fakeAction{name: "withdraw"}
+	m := newTestManager(t, stateFile, rules, withdraw)
+
+	_, err := m.Disarm("paused", 48*time.Hour)
+	assert.Error(t, err, "超过最长解除布防时间")
+	_, err = m.Disarm("unknown", time.Hour)
+	assert.Error(t, err)
+
+	until, err := m.Disarm("", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 0, withdraw.calls)
+
+	// 解除布防在重启后保留
+	m = newTestManager(t, stateFile, rules, withdraw)
+	assert.WithinDuration(t, until, m.Disarmed()[DisarmAll], time.Second)
+
+	require.NoError(t, m.Arm(""))
+	assert.Empty(t, m.Disarmed())
+	require.NoError(t, m.CheckAlert(context.Background(), "paused", 1))
+	assert.Equal(t, 1, withdraw.calls)
+}
+
+// TestManager_ManualTrigger 测试手动触发需要先预览，并受冷却时间限制
+func TestManager_ManualTrigger(t *testing.T) {
+	withdraw := &fakeAction{name: "withdraw", cooldown: time.Hour}
+	m := newTestManager(t, "", nil, withdraw)
+	ctx := context.Background()
+
+	_, err := m.Trigger(ctx, "withdraw", "维护演练")
+	assert.ErrorContains(t, err, "需要先预览")
+	_, err = m.Preview(ctx, "unknown")
+	assert.Error(t, err)
+
+	preview, err := m.Preview(ctx, "withdraw")
+	require.NoError(t, err)
+	require.NotNil(t, preview.ValidUntil)
+	_, err = m.Trigger(ctx, "withdraw", "维护演练")
+	require.NoError(t, err)
+	assert.Equal(t, 1, withdraw.calls)
+
+	states := m.State()
+	require.Len(t, states, 1)
+	assert.Equal(t, ManualRule, states[0].Rule)
+	assert.Equal(t, "手动触发: 维护演练", states[0].LastReason)
+
+	// 重新布防 manual 规则前不能再次手动触发
+	_, err = m.Trigger(ctx, "withdraw", "again")
+	assert.ErrorContains(t, err, "需要先重新布防 manual 规则")
+	require.NoError(t, m.Rearm(ManualRule))
+
+	// 每次预览只能触发一次，冷却期内无法再次触发
+	_, err = m.Trigger(ctx, "withdraw", "again")
+	assert.ErrorContains(t, err, "需要先预览")
+	preview, err = m.Preview(ctx, "withdraw")
+	require.NoError(t, err)
+	assert.Greater(t, preview.Cooldown, time.Duration(0))
+	_, err = m.Trigger(ctx, "withdraw", "again")
+	assert.ErrorContains(t, err, "冷却期")
+	assert.Equal(t, 1, withdraw.calls)
+}
+
+// TestManager_ManualTriggerLatch 测试交易类动作已由规则触发或交易结果未知时拒绝手动触发
+func TestManager_ManualTriggerLatch(t *testing.T) {
+	withdraw := &fakeAction{name: "withdraw", result: &contracts.TxResult{Status: contracts.TxStatusPending}, err: errors.New("receipt timeout")}
+	m := newTestManager(t, "", []config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+	}, withdraw)
+	ctx := context.Background()
+
+	require.Error(t, m.CheckAlert(ctx, "paused", 1))
+	_, err := m.Preview(ctx, "withdraw")
+	require.NoError(t, err)
+	_, err = m.Trigger(ctx, "withdraw", "重试")
+	assert.ErrorContains(t, err, "已由规则 paused 触发")
+	assert.Equal(t, 1, withdraw.calls)
+
+	// 确认交易结果并重新布防后可以手动触发
+	require.NoError(t, m.Rearm("paused"))
+	withdraw.result, withdraw.err = &contracts.TxResult{Status: contracts.TxStatusSuccess}, nil
+	_, err = m.Trigger(ctx, "withdraw", "重试")
+	require.NoError(t, err)
+	assert.Equal(t, 2, withdraw.calls)
+}
+
+// TestManager_Counters 测试应急动作结果和交易状态的计数
+func TestManager_Counters(t *testing.T) {
+	withdraw := &fakeAction{name: "withdraw", cooldown: time.Hour}
+	m := newTestManager(t, "", []config.AlertRuleConfig{
+		{Name: "paused", Metric: "paused", Operator: OpEqual, Threshold: 1, Actions: []string{"withdraw"}},
+		{Name: "spread", Metric: "spread", Operator: OpGreater, Threshold: 0.05, Actions: []string{"withdraw"}},
+	}, withdraw)
+	m.metrics = metrics.NewMetrics(&config.PrometheusConfig{}, zap.NewNop())
+	ctx := context.Background()
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		m.metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		return rec.Body.String()
+	}
+
+	// 执行前失败（没有交易结果）计入 failed，交易状态记为 failed
+	withdraw.err = errors.New("sign failed")
+	require.Error(t, m.CheckAlert(ctx, "paused", 1))
+	body := scrape()
+	assert.Contains(t, body, `ink_eth_monitor_emergency_actions_total{action="withdraw",outcome="failed"} 1`)
+	assert.Contains(t, body, `ink_eth_monitor_emergency_tx_total{action="withdraw",status="failed"} 1`)
+	assert.Contains(t, body, `ink_eth_monitor_emergency_triggers_total{action="withdraw",rule="paused"} 1`)
+
+	// 交易超时未上链计入 unknown，不计入 failed
+	withdraw.result, withdraw.err = &contracts.TxResult{Status: contracts.TxStatusPending}, errors.New("receipt timeout")
+	require.Error(t, m.CheckAlert(ctx, "paused", 1))
+	body = scrape()
+	assert.Contains(t, body, `ink_eth_monitor_emergency_actions_total{action="withdraw",outcome="unknown"} 1`)
+	assert.Contains(t, body, `ink_eth_monitor_emergency_actions_total{action="withdraw",outcome="failed"} 1`)
+	assert.Contains(t, body, `ink_eth_monitor_emergency_tx_total{action="withdraw",status="pending"} 1`)
+	require.NoError(t, m.Rearm("paused"))
+
+	// 冷却期内跳过
+	require.NoError(t, m.CheckAlert(ctx, "spread", 0.1))
+	assert.Contains(t, scrape(), `ink_eth_monitor_emergency_actions_total{action="withdraw",outcome="cooldown"} 1`)
+
+	// 解除布防期间跳过
+	_, err := m.Disarm("", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, m.CheckAlert(ctx, "spread", 0.1))
+	assert.Contains(t, scrape(), `ink_eth_monitor_emergency_actions_total{action="withdraw",outcome="disarmed"} 1`)
+	assert.Equal(t, 2, withdraw.calls)
+}
@@ -2,22 +2,55 @@ package metrics
 
 import (
 	"fmt"
+	"math"
+	"net/http"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/client_golang/prometheus/push"
 	"go.uber.org/zap"
 
 	"cs-projects-ink-eth-monitor/internal/config"
 )
 
+// latencyBuckets RPC请求和合约检查耗时的直方图区间，10ms 到约 20s
+var latencyBuckets = prometheus.ExponentialBuckets(0.01, 2, 12)
+
 // Metrics 指标管理器
 type Metrics struct {
-	pusher         *push.Pusher
+	registry       *prometheus.Registry // 推送和抓取共用的监控指标
+	runtime        *prometheus.Registry // Go 运行时和进程指标，只提供抓取
+	pusher         *push.Pusher         // 未启用推送时为 nil
 	logger         *zap.Logger
 	gatewayURL     string
 	jobName        string
 	contractGauges map[string]prometheus.Gauge
+	deletedGauges  map[string]bool             // 检查失败后按 delete 处理、暂时取消注册的合约指标
+	extraGauges    map[string]prometheus.Gauge // 链存活检查和合约附加指标，按指标名称索引
+	extraOwners    map[string][]string         // 合约附加指标和价格偏差指标所属的合约，按 chain_contract 索引
+	deletedExtras  map[string]bool             // 所属合约检查失败后按 delete 处理、暂时取消注册的附加指标
+	emergencyTx    *prometheus.CounterVec
+	emergencyFired *prometheus.CounterVec
+	emergencyActs  *prometheus.CounterVec
+	rpcDuration    *prometheus.HistogramVec
+	rpcErrors      *prometheus.CounterVec
+	checkDuration  *prometheus.HistogramVec
+	checkRetries   *prometheus.CounterVec
+	contractUpdate *prometheus.GaugeVec
+	contractUp     *prometheus.GaugeVec
+	endpointUp     *prometheus.GaugeVec
+	endpointLag    *prometheus.GaugeVec
+	endpointRTT    *prometheus.GaugeVec
+	endpointErrors *prometheus.GaugeVec
+	quorumDisagree *prometheus.CounterVec
+	contractBlock  *prometheus.GaugeVec
+	chainHead      *prometheus.GaugeVec
+	chainHeadTime  *prometheus.GaugeVec
+	heads          map[string]uint64
 	mu             sync.RWMutex
 }
 
@@ -28,14 +61,190 @@ func NewMetrics(cfg *config.PrometheusConfig, logger *zap.Logger) *Metrics {
 		gatewayURL:     cfg.GatewayURL,
 		jobName:        cfg.JobName,
 		contractGauges: make(map[string]prometheus.Gauge),
+		deletedGauges:  make(map[string]bool),
+		extraGauges:    make(map[string]prometheus.Gauge),
+		extraOwners:    make(map[string][]string),
+		deletedExtras:  make(map[string]bool),
+		heads:          make(map[string]uint64),
+		registry:       prometheus.NewRegistry(),
+		runtime:        prometheus.NewRegistry(),
 	}
 
-	// 创建pusher
-	m.pusher = push.New(cfg.GatewayURL, cfg.JobName)
+	// 应急交易结果计数
+	m.emergencyTx = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ink_eth_monitor_emergency_tx_total",
+		Help: "Emergency transactions by action and final status, only for actions that build a transaction",
+	}, []string{"action", "status"})
+
+	// 应急规则触发和动作执行结果计数
+	m.emergencyFired = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ink_eth_monitor_emergency_triggers_total",
+		Help: "Emergency actions triggered by rule (manual for the admin API)",
+	}, []string{"rule", "action"})
+	m.emergencyActs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ink_eth_monitor_emergency_actions_total",
+		Help: "Emergency action outcomes per triggered action, including notify actions and skips: success, failed, unknown (tx pending), cooldown, disarmed",
+	}, []string{"action", "outcome"})
+
+	// RPC请求延迟和错误
+	m.rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ink_eth_monitor_rpc_request_duration_seconds",
+		Help:    "RPC request latency by chain and JSON-RPC method",
+		Buckets: latencyBuckets,
+	}, []string{"chain", "method"})
+	m.rpcErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ink_eth_monitor_rpc_errors_total",
+		Help: "Failed RPC requests by chain, endpoint, method and error class",
+	}, []string{"chain", "endpoint", "method", "class"})
+
+	// 合约检查耗时、重试次数和最近一次成功更新的时间
+	contractLabels := []string{"chain", "contract"}
+	m.checkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ink_eth_monitor_check_duration_seconds",
+		Help:    "Duration of a contract check including retries",
+		Buckets: latencyBuckets,
+	}, contractLabels)
+	m.checkRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ink_eth_monitor_check_retries_total",
+		Help: "Retry attempts of contract checks",
+	}, contractLabels)
+	m.contractUpdate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_contract_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful update of the contract metric",
+	}, contractLabels)
+	m.contractUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_contract_up",
+		Help: "Whether the latest check of the contract succeeded (1) or failed after retries (0)",
+	}, contractLabels)
+
+	// RPC节点健康状态
+	endpointLabels := []string{"chain", "endpoint"}
+	m.endpointUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_rpc_endpoint_up",
+		Help: "Whether the RPC endpoint is considered healthy (1) or not (0)",
+	}, endpointLabels)
+	m.endpointLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_rpc_endpoint_block_lag",
+		Help: "Blocks the RPC endpoint is behind the highest endpoint of the same chain",
+	}, endpointLabels)
+	m.endpointRTT = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_rpc_endpoint_latency_seconds",
+		Help: "Moving average request latency of the RPC endpoint",
+	}, endpointLabels)
+	m.endpointErrors = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_rpc_endpoint_error_rate",
+		Help: "Moving average error rate of the RPC endpoint",
+	}, endpointLabels)
+
+	// 多节点交叉验证不一致计数
+	m.quorumDisagree = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ink_eth_monitor_quorum_disagreements_total",
+		Help: "Quorum reads where RPC providers returned different values, by whether quorum was still reached",
+	}, []string{"chain", "contract", "quorum"})
+
+	// 合约指标读取的区块
+	m.contractBlock = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_contract_block",
+		Help: "Block number the latest contract metric value was read at",
+	}, []string{"chain", "contract"})
+
+	// 链的最新区块，区块高度长时间不变说明链停止出块或节点不同步
+	m.chainHead = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_chain_head_block",
+		Help: "Latest block number observed on the chain",
+	}, []string{"chain"})
+	m.chainHeadTime = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ink_eth_monitor_chain_head_updated_timestamp_seconds",
+		Help: "Unix time when the observed chain head last advanced",
+	}, []string{"chain"})
+
+	m.registry.MustRegister(
+		m.emergencyTx,
+		m.emergencyFired,
+		m.emergencyActs,
+		m.rpcDuration,
+		m.rpcErrors,
+		m.checkDuration,
+		m.checkRetries,
+		m.contractUpdate,
+		m.contractUp,
+		m.endpointUp,
+		m.endpointLag,
+		m.endpointRTT,
+		m.endpointErrors,
+		m.quorumDisagree,
+		m.contractBlock,
+		m.chainHead,
+		m.chainHeadTime,
+	)
+	m.runtime.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	// 创建pusher，推送的指标与 /metrics 抓取的相同（不含运行时指标）
+	if cfg.PushEnabled() {
+		m.pusher = push.New(cfg.GatewayURL, cfg.JobName).Gatherer(m.registry)
+	}
 
 	return m
 }
 
+// Handler 返回 /metrics 抓取接口，包括监控指标和 Go 运行时、进程指标
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(prometheus.Gatherers{m.registry, m.runtime}, promhttp.HandlerOpts{})
+}
+
+// 链存活检查项
+const (
+	ChainHeadAge     = "head_age_seconds"     // 最新区块时间戳距今的秒数
+	ChainHeadAdvance = "head_advance_blocks"  // 两次检查之间区块高度增加的数量
+	ChainHeadLag     = "head_lag_blocks"      // 落后最高节点最多的节点的区块数
+	ChainL1OriginLag = "l1_origin_lag_blocks" // L2 记录的 L1 来源区块落后 L1 最新区块的数量
+)
+
+// ChainChecks 返回链存活检查导出的指标，L1 来源区块只对 INK 检查
+func ChainChecks(chain string) []string {
+	checks := []string{ChainHeadAge, ChainHeadAdvance, ChainHeadLag}
+	if chain == "ink" {
+		checks = append(checks, ChainL1OriginLag)
+	}
+	return checks
+}
+
+// GetChainMetricName 返回链存活检查的指标名称，如 ink_eth_monitor_ink_head_age_seconds
+func GetChainMetricName(chain, check string) string {
+	return fmt.Sprintf("ink_eth_monitor_%s_%s", chain, check)
+}
+
+// GetDetailMetricName 返回合约附加指标的名称，如 ink_eth_monitor_ink_chaos_push_oracle_round_stale
+func GetDetailMetricName(chain, contractName, detail string) string {
+	return fmt.Sprintf("ink_eth_monitor_%s_%s_%s", chain, contractName, detail)
+}
+
+// GetPriceDeviationMetricName 返回资产价格偏差的指标名称，如 ink_eth_monitor_price_deviation_wsteth
+func GetPriceDeviationMetricName(asset string) string {
+	return "ink_eth_monitor_price_deviation_" + metricSuffix(asset)
+}
+
+// GetPriceReferenceMetricName 返回资产参考价格的指标名称，如 ink_eth_monitor_price_reference_wsteth
+func GetPriceReferenceMetricName(asset string) string {
+	return "ink_eth_monitor_price_reference_" + metricSuffix(asset)
+}
+
+// metricSuffix 将名称转换为指标名称可用的小写形式
+func metricSuffix(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '_'
+	}, name)
+}
+
 // GetMetricName 根据链和合约名称返回自定义的指标名称
 func GetMetricName(chain, contractName string) string {
 	key := fmt.Sprintf("%s_%s", chain, contractName)
@@ -81,7 +290,10 @@ func (m *Metrics) RegisterContractMetric(chain, contractName string) {
 	})
 
 	m.contractGauges[key] = gauge
-	m.pusher.Collector(gauge)
+	if err := m.registry.Register(gauge); err != nil {
+		m.logger.Error("注册合约指标失败", zap.String("metric_name", metricName), zap.Error(err))
+		return
+	}
 
 	m.logger.Info("注册合约指标",
 		zap.String("chain", chain),
@@ -90,14 +302,20 @@ func (m *Metrics) RegisterContractMetric(chain, contractName string) {
 	)
 }
 
-// SetContractMetric 设置合约指标值
+// SetContractMetric 设置合约指标值，按 delete 处理过的指标重新注册
 func (m *Metrics) SetContractMetric(chain, contractName string, value float64) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
 	key := fmt.Sprintf("%s_%s", chain, contractName)
 
 	if gauge, exists := m.contractGauges[key]; exists {
+		if m.deletedGauges[key] {
+			if err := m.registry.Register(gauge); err != nil {
+				m.logger.Error("重新注册合约指标失败", zap.String("key", key), zap.Error(err))
+			}
+			delete(m.deletedGauges, key)
+		}
 		gauge.Set(value)
 		m.logger.Debug("设置指标值",
 			zap.String("key", key),
@@ -110,8 +328,176 @@ func (m *Metrics) SetContractMetric(chain, contractName string, value float64) {
 	}
 }
 
-// Push 推送指标到Gateway
+// MarkContractStale 按 policy（config.OnFailure*）处理检查失败的合约指标，包括合约的附加指标和价格偏差指标
+// nan 将指标设置为 NaN；delete 取消注册指标并删除读取区块，之后的推送和抓取不再包含，下次设置值时恢复
+func (m *Metrics) MarkContractStale(chain, contractName, policy string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s_%s", chain, contractName)
+	if gauge, exists := m.contractGauges[key]; exists {
+		switch policy {
+		case config.OnFailureNaN:
+			gauge.Set(math.NaN())
+		case config.OnFailureDelete:
+			if !m.deletedGauges[key] {
+				m.registry.Unregister(gauge)
+				m.deletedGauges[key] = true
+			}
+			m.contractBlock.DeleteLabelValues(chain, contractName)
+		}
+	}
+
+	for _, name := range m.extraOwners[key] {
+		gauge := m.extraGauges[name]
+		switch policy {
+		case config.OnFailureNaN:
+			gauge.Set(math.NaN())
+		case config.OnFailureDelete:
+			if !m.deletedExtras[name] {
+				m.registry.Unregister(gauge)
+				m.deletedExtras[name] = true
+			}
+		}
+	}
+}
+
+// SetContractUp 记录合约最近一次检查（含重试）是否成功
+func (m *Metrics) SetContractUp(chain, contractName string, up bool) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+	m.contractUp.WithLabelValues(chain, contractName).Set(value)
+}
+
+// SetChainMetric 设置链存活检查指标值，首次设置时注册指标
+func (m *Metrics) SetChainMetric(chain, check string, value float64) {
+	m.setExtra("", GetChainMetricName(chain, check), fmt.Sprintf("Chain liveness metric %s for %s", check, chain),
+		prometheus.Labels{"chain": chain}, value)
+}
+
+// SetContractDetail 设置合约附加指标值，首次设置时注册指标
+func (m *Metrics) SetContractDetail(chain, contractName, detail string, value float64) {
+	m.setExtra(fmt.Sprintf("%s_%s", chain, contractName), GetDetailMetricName(chain, contractName, detail),
+		fmt.Sprintf("Monitor metric %s for %s contract %s", detail, chain, contractName),
+		prometheus.Labels{"chain": chain, "contract": contractName}, value)
+}
+
+// SetPriceDeviation 设置资产价格偏差及参考价格（参考价格源的中位数），两项指标归属 chain 上的价格源 feed
+func (m *Metrics) SetPriceDeviation(chain, asset, feed string, deviation, reference float64) {
+	owner := fmt.Sprintf("%s_%s", chain, feed)
+	labels := prometheus.Labels{"asset": asset, "feed": feed}
+	m.setExtra(owner, GetPriceDeviationMetricName(asset), fmt.Sprintf("Price deviation of %s feed %s from the reference median", asset, feed), labels, deviation)
+	m.setExtra(owner, GetPriceReferenceMetricName(asset), fmt.Sprintf("Median reference price of %s", asset), labels, reference)
+}
+
+// setExtra 设置按名称注册的指标值，首次设置时注册指标，owner 非空时记录指标所属的合约（chain_contract）
+// 所属合约检查失败后被取消注册的指标在此重新注册
+// 与其他指标重名时注册失败，只记录错误日志（启动时已由监控器校验指标名称互不重复）
+func (m *Metrics) setExtra(owner, name, help string, labels prometheus.Labels, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	gauge, exists := m.extraGauges[name]
+	if !exists {
+		gauge = prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help, ConstLabels: labels})
+		m.extraGauges[name] = gauge
+		if owner != "" {
+			m.extraOwners[owner] = append(m.extraOwners[owner], name)
+		}
+		if err := m.registry.Register(gauge); err != nil {
+			m.logger.Error("注册指标失败", zap.String("metric_name", name), zap.Error(err))
+		}
+	} else if m.deletedExtras[name] {
+		if err := m.registry.Register(gauge); err != nil {
+			m.logger.Error("重新注册指标失败", zap.String("metric_name", name), zap.Error(err))
+		}
+		delete(m.deletedExtras, name)
+	}
+	gauge.Set(value)
+}
+
+// IncEmergencyTx 记录一笔应急交易的最终状态
+func (m *Metrics) IncEmergencyTx(action, status string) {
+	m.emergencyTx.WithLabelValues(action, status).Inc()
+}
+
+// IncEmergencyTrigger 记录一次应急动作触发，rule 为触发的规则
+func (m *Metrics) IncEmergencyTrigger(rule, action string) {
+	m.emergencyFired.WithLabelValues(rule, action).Inc()
+}
+
+// IncEmergencyAction 记录一次应急动作的执行结果
+func (m *Metrics) IncEmergencyAction(action, outcome string) {
+	m.emergencyActs.WithLabelValues(action, outcome).Inc()
+}
+
+// ObserveRPC 记录一次RPC请求的延迟，class 不为空时计入错误（实现 client.RPCObserver）
+func (m *Metrics) ObserveRPC(chain, endpoint, method string, latency time.Duration, class string) {
+	m.rpcDuration.WithLabelValues(chain, method).Observe(latency.Seconds())
+	if class != "" {
+		m.rpcErrors.WithLabelValues(chain, endpoint, method, class).Inc()
+	}
+}
+
+// ObserveCheck 记录一次合约检查（包括重试）的耗时
+func (m *Metrics) ObserveCheck(chain, contractName string, d time.Duration) {
+	m.checkDuration.WithLabelValues(chain, contractName).Observe(d.Seconds())
+}
+
+// IncCheckRetry 记录一次合约检查的重试
+func (m *Metrics) IncCheckRetry(chain, contractName string) {
+	m.checkRetries.WithLabelValues(chain, contractName).Inc()
+}
+
+// SetContractUpdated 记录合约指标最近一次成功更新的时间，用于区分正常的 0 和过期的值
+func (m *Metrics) SetContractUpdated(chain, contractName string) {
+	m.contractUpdate.WithLabelValues(chain, contractName).SetToCurrentTime()
+}
+
+// SetEndpointHealth 设置RPC节点健康状态指标，endpoint 应为脱敏后的节点名称
+func (m *Metrics) SetEndpointHealth(chain, endpoint string, healthy bool, lag uint64, latency time.Duration, errorRate float64) {
+	up := 0.0
+	if healthy {
+		up = 1
+	}
+	m.endpointUp.WithLabelValues(chain, endpoint).Set(up)
+	m.endpointLag.WithLabelValues(chain, endpoint).Set(float64(lag))
+	m.endpointRTT.WithLabelValues(chain, endpoint).Set(latency.Seconds())
+	m.endpointErrors.WithLabelValues(chain, endpoint).Set(errorRate)
+}
+
+// IncQuorumDisagreement 记录一次多节点读取结果不一致，reached 表示是否仍达到法定一致数
+func (m *Metrics) IncQuorumDisagreement(chain, contractName string, reached bool) {
+	m.quorumDisagree.WithLabelValues(chain, contractName, fmt.Sprintf("%t", reached)).Inc()
+}
+
+// SetContractBlock 记录合约指标值读取的区块，block 为 0（未固定区块）时不更新
+func (m *Metrics) SetContractBlock(chain, contractName string, block uint64) {
+	if block == 0 {
+		return
+	}
+	m.contractBlock.WithLabelValues(chain, contractName).Set(float64(block))
+}
+
+// SetChainHead 记录链的最新区块，区块高度增加时更新时间戳
+func (m *Metrics) SetChainHead(chain string, block uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if block <= m.heads[chain] {
+		return
+	}
+	m.heads[chain] = block
+	m.chainHead.WithLabelValues(chain).Set(float64(block))
+	m.chainHeadTime.WithLabelValues(chain).SetToCurrentTime()
+}
+
+// Push 推送指标到Gateway，未启用推送时不执行任何操作
 func (m *Metrics) Push() error {
+	if m.pusher == nil {
+		return nil
+	}
 	if err := m.pusher.Push(); err != nil {
 		m.logger.Error("推送指标失败", zap.Error(err))
 		return fmt.Errorf("推送指标到Prometheus Gateway失败: %w", err)
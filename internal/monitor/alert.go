@@ -0,0 +1,151 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// evaluateAlert 检查合约配置的告警条件，返回最终写入指标的值
+// price_diff 告警会将指标值替换为与对比价格源的偏差（如 0.03 表示 3%）
+func (m *Monitor) evaluateAlert(ctx context.Context, snap *snapshot, chain string, contract contracts.Account, value float64) (float64, error) {
+	alert, ok := m.alerts[metricKey(chain, contract.Name())]
+	if !ok {
+		return value, nil
+	}
+
+	switch alert.Type {
+	case config.AlertTypePriceDiff:
+		return m.checkPriceDiff(ctx, snap, chain, contract, value, alert)
+	case config.AlertTypeSupplyDiff:
+		return value, m.checkSupplyDiff(ctx, snap, chain, contract, value, alert)
+	}
+	return value, nil
+}
+
+// checkPriceDiff 与 compare_with 指定的价格源比较偏差
+func (m *Monitor) checkPriceDiff(ctx context.Context, snap *snapshot, chain string, contract contracts.Account, price float64, alert *config.AlertConfig) (float64, error) {
+	compareChain, compareAccount, ok := m.findAccount(alert.CompareWith)
+	if !ok {
+		return 0, fmt.Errorf("对比合约不存在: %s", alert.CompareWith)
+	}
+
+	comparePrice, err := m.readCompare(ctx, snap, chain, contract, compareChain, compareAccount)
+	if err != nil {
+		return 0, fmt.Errorf("获取对比价格失败: %w", err)
+	}
+
+	var deviation float64
+	if comparePrice != 0 {
+		deviation = math.Abs(price-comparePrice) / comparePrice
+	}
+
+	threshold := alert.GetThresholdFloat()
+	if deviation > threshold {
+		m.logger.Warn("价格偏差超过阈值",
+			zap.String("chain", chain),
+			zap.String("contract", contract.Name()),
+			zap.String("compare_with", alert.CompareWith),
+			zap.Float64("price", price),
+			zap.Float64("compare_price", comparePrice),
+			zap.Float64("deviation", deviation),
+			zap.Float64("threshold", threshold),
+			zap.Uint64("block", snap.block(chain)),
+			zap.Uint64("compare_block", snap.block(compareChain)),
+		)
+	}
+
+	return deviation, nil
+}
+
+// readCompare 读取 price_diff 的对比价格
+// 偏差替换了合约自身的指标值，该指标关联了应急动作且配置了 emergency.quorum 时对比价格源同样进行多节点交叉验证
+func (m *Monitor) readCompare(ctx context.Context, snap *snapshot, chain string, contract contracts.Account, compareChain string, compareAccount contracts.Account) (float64, error) {
+	if m.cfg.Emergency.Quorum.Min <= 0 || !m.emergency.Guards(metrics.GetMetricName(chain, contract.Name())) {
+		return compareAccount.Monitor(ctx, snap.caller(compareChain))
+	}
+	reading, err := m.quorumRead(ctx, snap, compareChain, compareAccount)
+	if err != nil {
+		return 0, fmt.Errorf("多节点交叉验证失败: %w", err)
+	}
+	return reading.Value, nil
+}
+
+// checkSupplyDiff 读取 compare_address 的供应量，与指标值的差额超过阈值时告警
+func (m *Monitor) checkSupplyDiff(ctx context.Context, snap *snapshot, chain string, contract contracts.Account, value float64, alert *config.AlertConfig) error {
+	method := supplyMethod(alert)
+	supply, err := snap.caller(chain).CallUint256(ctx, common.HexToAddress(alert.CompareAddress).Hex(), supplyMethodID(alert))
+	if err != nil {
+		return fmt.Errorf("调用 %s 失败: %w", method, err)
+	}
+
+	supplyFloat, _ := new(big.Float).SetInt(supply).Float64()
+	diff := supplyFloat - value
+
+	threshold, err := alert.ParseThreshold()
+	if err != nil {
+		return fmt.Errorf("告警阈值%w", err)
+	}
+
+	if diff > threshold {
+		m.logger.Warn("供应量差异超过阈值",
+			zap.String("chain", chain),
+			zap.String("contract", contract.Name()),
+			zap.String("compare_address", alert.CompareAddress),
+			zap.String("compare_method", method),
+			zap.Float64("value", value),
+			zap.Float64("supply", supplyFloat),
+			zap.Float64("diff", diff),
+			zap.Float64("threshold", threshold),
+			zap.Uint64("block", snap.block(chain)),
+		)
+	}
+
+	return nil
+}
+
+// supplyMethod 返回 supply_diff 告警读取供应量的方法名，默认 totalSupply
+func supplyMethod(alert *config.AlertConfig) string {
+	if alert.CompareMethod == "" {
+		return "totalSupply"
+	}
+	return alert.CompareMethod
+}
+
+// supplyMethodID 返回 supply_diff 告警读取供应量的调用数据
+func supplyMethodID(alert *config.AlertConfig) []byte {
+	return crypto.Keccak256([]byte(supplyMethod(alert) + "()"))[:4]
+}
+
+// findAccount 按名称查找监控合约，返回所在链
+func (m *Monitor) findAccount(name string) (string, contracts.Account, bool) {
+	for _, account := range m.ethAccounts {
+		if account.Name() == name {
+			return "ethereum", account, true
+		}
+	}
+	for _, account := range m.inkAccounts {
+		if account.Name() == name {
+			return "ink", account, true
+		}
+	}
+	return "", nil, false
+}
+
+// chainClient 返回链对应的合约调用器
+func (m *Monitor) chainClient(chain string) *client.ContractCaller {
+	if chain == "ethereum" {
+		return m.clientManager.GetEthereumClient()
+	}
+	return m.clientManager.GetInkClient()
+}
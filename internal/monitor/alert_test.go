@@ -0,0 +1,128 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// TestEvaluateAlert_PriceDiff 测试 price_diff 告警将指标值替换为与对比价格源的偏差
+func TestEvaluateAlert_PriceDiff(t *testing.T) {
+	m := newTestMonitor(t, &config.Config{Monitor: config.MonitorConfig{PollInterval: 30}})
+	core, logs := observer.New(zap.WarnLevel)
+	m.logger = zap.New(core)
+
+	feed := newFixedPrice("ink_feed", 1030, nil)
+	compare := newFixedPrice("eth_feed", 1000, nil)
+	m.inkAccounts = []contracts.Account{feed}
+	m.ethAccounts = []contracts.Account{compare}
+	m.alerts[metricKey("ink", "ink_feed")] = &config.AlertConfig{Type: config.AlertTypePriceDiff, CompareWith: "eth_feed", Threshold: 0.05}
+
+	value, err := m.evaluateAlert(context.Background(), &snapshot{}, "ink", feed, 1030)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.03, value, 1e-9)
+	assert.Zero(t, logs.FilterMessage("价格偏差超过阈值").Len())
+
+	value, err = m.evaluateAlert(context.Background(), &snapshot{}, "ink", feed, 1100)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.1, value, 1e-9)
+	assert.Equal(t, 1, logs.FilterMessage("价格偏差超过阈值").Len())
+
+	// 对比价格源读取失败或不存在
+	compare.err = errors.New("down")
+	_, err = m.evaluateAlert(context.Background(), &snapshot{}, "ink", feed, 1030)
+	assert.ErrorContains(t, err, "获取对比价格失败")
+	m.alerts[metricKey("ink", "ink_feed")].CompareWith = "missing"
+	_, err = m.evaluateAlert(context.Background(), &snapshot{}, "ink", feed, 1030)
+	assert.ErrorContains(t, err, "对比合约不存在")
+
+	// 未配置告警的合约指标值不变
+	value, err = m.evaluateAlert(context.Background(), &snapshot{}, "ethereum", compare, 42)
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, value)
+}
+
+// TestEvaluateAlert_SupplyDiff 测试 supply_diff 告警读取 compare_address 的供应量并与指标值比较
+func TestEvaluateAlert_SupplyDiff(t *testing.T) {
+	cfg := &config.Config{InkRPC: newChainNode(t, &chainNode{head: 100, l1Origin: 1500}), Monitor: config.MonitorConfig{PollInterval: 30}}
+	m := newTestMonitor(t, cfg)
+	core, logs := observer.New(zap.WarnLevel)
+	m.logger = zap.New(core)
+
+	caller, err := client.NewContractCaller(cfg.InkRPC, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(caller.Close)
+	snap := &snapshot{callers: map[string]*client.ContractCaller{"ink": caller}}
+
+	token := newFixedPrice("ink_token", 0, nil)
+	alert := &config.AlertConfig{Type: config.AlertTypeSupplyDiff, CompareAddress: common.HexToAddress("0x01").Hex(), Threshold: "100"}
+	m.alerts[metricKey("ink", "ink_token")] = alert
+
+	// 供应量 1500 - 指标值 1450 未超过阈值，指标值保持不变
+	value, err := m.evaluateAlert(context.Background(), snap, "ink", token, 1450)
+	require.NoError(t, err)
+	assert.Equal(t, 1450.0, value)
+	assert.Zero(t, logs.FilterMessage("供应量差异超过阈值").Len())
+
+	_, err = m.evaluateAlert(context.Background(), snap, "ink", token, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1, logs.FilterMessage("供应量差异超过阈值").Len())
+
+	alert.Threshold = "abc"
+	_, err = m.evaluateAlert(context.Background(), snap, "ink", token, 1000)
+	assert.ErrorContains(t, err, "无法解析")
+}
+
+// TestEvaluateAlert_PriceDiffQuorum 测试 price_diff 的合约指标关联了应急动作时对比价格源经过多节点交叉验证
+func TestEvaluateAlert_PriceDiffQuorum(t *testing.T) {
+	nodes := []*chainNode{{head: 100, l1Origin: 1000e8}, {head: 100, l1Origin: 1000e8}}
+	cfg := &config.Config{
+		Monitor:   config.MonitorConfig{PollInterval: 30},
+		Emergency: config.EmergencyConfig{Quorum: config.QuorumConfig{Min: 2}},
+	}
+	for i := 0; i < 3; i++ {
+		cfg.Ink.RpcURLs = append(cfg.Ink.RpcURLs, newChainNode(t, &chainNode{head: 100}))
+	}
+	for _, node := range nodes {
+		cfg.Ethereum.RpcURLs = append(cfg.Ethereum.RpcURLs, newChainNode(t, node))
+	}
+	cfg.InkRPC, cfg.EthRPC = cfg.Ink.RpcURLs[0], cfg.Ethereum.RpcURLs[0]
+	clientManager, err := client.NewClientManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(clientManager.Close)
+
+	m := newTestMonitor(t, cfg)
+	m.clientManager = clientManager
+	m.emergency = newGuardingManager(t, cfg, config.AlertRuleConfig{
+		Name: "diff", Metric: metrics.GetMetricName("ink", "ink_feed"), Operator: ">", Threshold: 0.05, Actions: []string{"page"},
+	})
+	feed := newFixedPrice("ink_feed", 1030, nil)
+	m.inkAccounts = []contracts.Account{feed}
+	m.ethAccounts = []contracts.Account{contracts.NewPriceFeed("eth_feed", common.HexToAddress("0x01"), "latestAnswer", 8)}
+	m.alerts[metricKey("ink", "ink_feed")] = &config.AlertConfig{Type: config.AlertTypePriceDiff, CompareWith: "eth_feed", Threshold: 0.05}
+
+	value, err := m.evaluateAlert(context.Background(), &snapshot{}, "ink", feed, 1030)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.03, value, 1e-9)
+
+	// 对比价格源在节点之间不一致时本轮检查失败
+	nodes[1].l1Origin = 900e8
+	_, err = m.evaluateAlert(context.Background(), &snapshot{}, "ink", feed, 1030)
+	assert.ErrorContains(t, err, "多节点交叉验证失败")
+
+	// 对比价格源所在的以太坊同样需要达到法定一致数
+	require.NoError(t, m.validateQuorum())
+	cfg.Emergency.Quorum.Min = 3
+	assert.ErrorContains(t, m.validateQuorum(), "ethereum 的指标关联了应急动作")
+}
@@ -0,0 +1,199 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// chainlinkETHUSD 以太坊主网 Chainlink ETH/USD 预言机地址，用于默认的跨链价格偏差检查
+const chainlinkETHUSD = "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"
+
+// deviationCheck 价格源与参考价格源中位数的偏差检查
+type deviationCheck struct {
+	chain      string // 价格源所在的链
+	feed       string // 价格源（监控合约）的名称
+	asset      string
+	threshold  float64
+	minRefs    int
+	references []reference
+	replace    bool // 用偏差替换价格源自身的指标值（默认 chaos_push_oracle 检查的 oracle_price_spread）
+}
+
+// reference 参考价格源
+type reference struct {
+	chain   string
+	account contracts.Account
+}
+
+// buildDeviations 根据 price_deviations 配置创建价格偏差检查
+// 未配置时保留默认的跨链价格偏差检查：chaos_push_oracle 与以太坊主网 Chainlink ETH/USD 比较，偏差写入其自身的指标
+func (m *Monitor) buildDeviations() error {
+	for i, cfg := range m.cfg.PriceDeviations {
+		chain, feed, ok := m.findAccount(cfg.Feed)
+		if !ok {
+			return fmt.Errorf("price_deviations[%d] 引用了不存在的价格源: %s", i, cfg.Feed)
+		}
+		key := metricKey(chain, feed.Name())
+		if _, exists := m.deviations[key]; exists {
+			return fmt.Errorf("price_deviations[%d] 的价格源重复: %s", i, cfg.Feed)
+		}
+
+		check := &deviationCheck{chain: chain, feed: feed.Name(), asset: cfg.Asset, threshold: cfg.Threshold, minRefs: cfg.GetMinReferences()}
+		for j, refCfg := range cfg.References {
+			ref, err := m.newReference(cfg.Asset, j, refCfg)
+			if err != nil {
+				return fmt.Errorf("price_deviations[%d].references[%d]: %w", i, j, err)
+			}
+			check.references = append(check.references, ref)
+		}
+		m.deviations[key] = check
+	}
+	if len(m.cfg.PriceDeviations) > 0 {
+		return nil
+	}
+
+	for _, account := range m.inkAccounts {
+		if account.Type() == contracts.TypePriceFeed && account.Name() == "chaos_push_oracle" && m.alerts[metricKey("ink", account.Name())] == nil {
+			chainlink := contracts.NewRoundFeed("chainlink_eth_usd", common.HexToAddress(chainlinkETHUSD), 0, 0)
+			m.deviations[metricKey("ink", account.Name())] = &deviationCheck{
+				chain:      "ink",
+				feed:       account.Name(),
+				asset:      "ETH",
+				minRefs:    1,
+				references: []reference{{chain: "ethereum", account: chainlink}},
+				replace:    true,
+			}
+		}
+	}
+	return nil
+}
+
+// newReference 创建参考价格源，引用监控合约或按链和地址创建价格源
+func (m *Monitor) newReference(asset string, index int, cfg config.ReferenceFeedConfig) (reference, error) {
+	if cfg.Name != "" {
+		chain, account, ok := m.findAccount(cfg.Name)
+		if !ok {
+			return reference{}, fmt.Errorf("引用了不存在的合约: %s", cfg.Name)
+		}
+		return reference{chain: chain, account: account}, nil
+	}
+
+	method := cfg.Method
+	if method == "" {
+		method = "latestRoundData"
+	}
+	account, err := contracts.NewAccount(config.ContractConfig{
+		Name:     fmt.Sprintf("%s_reference_%d", strings.ToLower(asset), index),
+		Address:  cfg.Address,
+		Type:     contracts.TypePriceFeed,
+		Method:   method,
+		Decimals: cfg.Decimals,
+	})
+	if err != nil {
+		return reference{}, err
+	}
+	return reference{chain: cfg.Chain, account: account}, nil
+}
+
+// checkDeviation 计算价格与参考价格源中位数的偏差，导出指标并评估应急告警规则，返回最终写入价格源指标的值
+// 各价格源按各自的精度换算后比较；读取失败的参考价格源不参与中位数，成功数量少于 min_references 时返回错误
+func (m *Monitor) checkDeviation(ctx context.Context, snap *snapshot, chain string, contract contracts.Account, price float64, trusted bool) (float64, error) {
+	check, ok := m.deviations[metricKey(chain, contract.Name())]
+	if !ok {
+		return price, nil
+	}
+
+	var (
+		prices []float64
+		errs   []error
+	)
+	for _, ref := range check.references {
+		p, err := m.readReference(ctx, snap, check, ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ref.account.Name(), err))
+			continue
+		}
+		prices = append(prices, p)
+	}
+	if len(prices) < check.minRefs {
+		return 0, fmt.Errorf("参考价格源读取成功 %d 个，少于 %d 个: %w", len(prices), check.minRefs, errors.Join(errs...))
+	}
+	if len(errs) > 0 {
+		m.logger.Warn("部分参考价格源读取失败", zap.String("asset", check.asset), zap.Error(errors.Join(errs...)))
+	}
+
+	ref := median(prices)
+	var deviation float64
+	if ref != 0 {
+		deviation = math.Abs(price-ref) / ref
+	}
+
+	m.metrics.SetPriceDeviation(chain, check.asset, contract.Name(), deviation, ref)
+	m.checkEmergency(ctx, contract, metrics.GetPriceDeviationMetricName(check.asset), deviation, trusted, snap.block(chain))
+
+	fields := []zap.Field{
+		zap.String("asset", check.asset),
+		zap.String("chain", chain),
+		zap.String("contract", contract.Name()),
+		zap.Float64("price", price),
+		zap.Float64("reference_price", ref),
+		zap.Float64s("reference_prices", prices),
+		zap.Float64("deviation", deviation),
+		zap.Float64("deviation_percent", deviation*100),
+		zap.Uint64("block", snap.block(chain)),
+	}
+	if check.threshold > 0 && deviation > check.threshold {
+		m.logger.Warn("价格偏差超过阈值", append(fields, zap.Float64("threshold", check.threshold))...)
+	} else {
+		m.logger.Info("检查价格偏差", fields...)
+	}
+
+	if check.replace {
+		return deviation, nil
+	}
+	return price, nil
+}
+
+// readReference 读取参考价格源
+// 偏差结果关联了应急动作且配置了 emergency.quorum 时进行多节点交叉验证，未达到法定一致数的参考价格源视为读取失败
+func (m *Monitor) readReference(ctx context.Context, snap *snapshot, check *deviationCheck, ref reference) (float64, error) {
+	if m.cfg.Emergency.Quorum.Min <= 0 || !m.guardsDeviation(check) {
+		return ref.account.Monitor(ctx, snap.caller(ref.chain))
+	}
+	reading, err := m.quorumRead(ctx, snap, ref.chain, ref.account)
+	if err != nil {
+		return 0, fmt.Errorf("多节点交叉验证失败: %w", err)
+	}
+	return reading.Value, nil
+}
+
+// guardsDeviation 检查偏差结果是否关联了应急动作
+// 除偏差指标外，replace 的检查将偏差写入价格源自身的指标，该指标的规则同样依赖参考价格源
+func (m *Monitor) guardsDeviation(check *deviationCheck) bool {
+	if m.emergency.Guards(metrics.GetPriceDeviationMetricName(check.asset)) {
+		return true
+	}
+	return check.replace && m.emergency.Guards(metrics.GetMetricName(check.chain, check.feed))
+}
+
+// median 返回中位数，偶数个时取中间两个的平均值
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
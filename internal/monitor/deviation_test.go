@@ -0,0 +1,186 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// fixedPrice 返回固定价格的测试价格源
+type fixedPrice struct {
+	contracts.BaseContract
+	price float64
+	err   error
+}
+
+func newFixedPrice(name string, price float64, err error) *fixedPrice {
+	return &fixedPrice{BaseContract: contracts.NewBaseContract(name, common.Address{}, contracts.TypePriceFeed), price: price, err: err}
+}
+
+func (p *fixedPrice) Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error) {
+	return p.price, p.err
+}
+
+// TestCheckDeviation 测试与多个参考价格源中位数的偏差
+func TestCheckDeviation(t *testing.T) {
+	cfg := &config.Config{
+		Monitor: config.MonitorConfig{PollInterval: 30},
+		PriceDeviations: []config.PriceDeviationConfig{{
+			Asset:         "wstETH",
+			Feed:          "ink_wsteth",
+			Threshold:     0.05,
+			MinReferences: 2,
+			References:    []config.ReferenceFeedConfig{{Name: "ref_a"}, {Name: "ref_b"}, {Name: "ref_c"}},
+		}},
+	}
+	m := newTestMonitor(t, cfg)
+	m.deviations = make(map[string]*deviationCheck)
+	feed := newFixedPrice("ink_wsteth", 3300, nil)
+	refB := newFixedPrice("ref_b", 3000, nil)
+	m.inkAccounts = []contracts.Account{feed}
+	m.ethAccounts = []contracts.Account{newFixedPrice("ref_a", 2900, nil), refB, newFixedPrice("ref_c", 0, errors.New("down"))}
+	require.NoError(t, m.buildDeviations())
+	require.Len(t, m.deviations[metricKey("ink", "ink_wsteth")].references, 3)
+
+	// 中位数为 (2900 + 3000) / 2，价格源自身的指标值不变
+	value, err := m.checkDeviation(context.Background(), &snapshot{}, "ink", feed, 3300, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3300.0, value)
+
+	m.deviations[metricKey("ink", "ink_wsteth")].replace = true
+	deviation, err := m.checkDeviation(context.Background(), &snapshot{}, "ink", feed, 3300, true)
+	require.NoError(t, err)
+	assert.InDelta(t, 350.0/2950, deviation, 1e-9)
+
+	// 成功读取的参考价格源少于 min_references
+	refB.err = errors.New("down")
+	_, err = m.checkDeviation(context.Background(), &snapshot{}, "ink", feed, 3300, true)
+	assert.ErrorContains(t, err, "少于 2 个")
+
+	// 未配置 price_deviations 时保留默认的 chaos_push_oracle 检查，偏差写入其自身的指标
+	m = newTestMonitor(t, &config.Config{Monitor: config.MonitorConfig{PollInterval: 30}})
+	m.deviations = make(map[string]*deviationCheck)
+	m.inkAccounts = []contracts.Account{contracts.NewChaosPushOracle(common.HexToAddress(contracts.DefaultL2ChaosPushOracle))}
+	require.NoError(t, m.buildDeviations())
+	check := m.deviations[metricKey("ink", "chaos_push_oracle")]
+	require.NotNil(t, check)
+	assert.True(t, check.replace)
+	assert.Equal(t, "ethereum", check.references[0].chain)
+}
+
+// TestCheckDeviation_Quorum 测试偏差指标关联了应急动作时参考价格源经过多节点交叉验证
+func TestCheckDeviation_Quorum(t *testing.T) {
+	// 参考价格源 latestAnswer 默认 8 位精度，价格为 3000
+	nodes := []*chainNode{{head: 100, l1Origin: 3000e8}, {head: 100, l1Origin: 3000e8}, {head: 100, l1Origin: 3000e8}}
+	cfg := &config.Config{
+		InkRPC:  newChainNode(t, &chainNode{head: 100}),
+		Ink:     config.ChainConfig{RpcURLs: []string{newChainNode(t, &chainNode{head: 100})}},
+		Monitor: config.MonitorConfig{PollInterval: 30},
+		PriceDeviations: []config.PriceDeviationConfig{{
+			Asset:      "wstETH",
+			Feed:       "ink_wsteth",
+			References: []config.ReferenceFeedConfig{{Chain: "ethereum", Address: common.HexToAddress("0x01").Hex(), Method: "latestAnswer"}},
+		}},
+		Emergency: config.EmergencyConfig{Quorum: config.QuorumConfig{Min: 2}},
+	}
+	for _, node := range nodes {
+		cfg.Ethereum.RpcURLs = append(cfg.Ethereum.RpcURLs, newChainNode(t, node))
+	}
+	cfg.EthRPC = cfg.Ethereum.RpcURLs[0]
+	clientManager, err := client.NewClientManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(clientManager.Close)
+
+	m := newTestMonitor(t, cfg)
+	m.clientManager = clientManager
+	m.emergency = newGuardingManager(t, cfg, config.AlertRuleConfig{
+		Name: "depeg", Metric: metrics.GetPriceDeviationMetricName("wstETH"), Operator: ">", Threshold: 0.05, Actions: []string{"page"},
+	})
+	m.deviations = make(map[string]*deviationCheck)
+	feed := newFixedPrice("ink_wsteth", 3300, nil)
+	m.inkAccounts = []contracts.Account{feed}
+	require.NoError(t, m.buildDeviations())
+	require.NoError(t, m.validateQuorum())
+	assert.True(t, m.guardsAccount("ink", feed), "价格源自身也需要交叉验证")
+
+	m.deviations[metricKey("ink", "ink_wsteth")].replace = true
+	deviation, err := m.checkDeviation(context.Background(), &snapshot{}, "ink", feed, 3300, true)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.1, deviation, 1e-9)
+
+	// 参考价格源未达到法定一致数时不参与中位数
+	nodes[1].l1Origin, nodes[2].l1Origin = 3100e8, 3200e8
+	_, err = m.checkDeviation(context.Background(), &snapshot{}, "ink", feed, 3300, true)
+	assert.ErrorContains(t, err, "多节点交叉验证失败")
+
+	// 价格源和参考价格源所在的链节点数都需要达到法定一致数
+	cfg.Emergency.Quorum.Min = 3
+	assert.ErrorContains(t, m.validateQuorum(), "ink 的指标关联了应急动作")
+	cfg.Emergency.Quorum.Min = 4
+	assert.ErrorContains(t, m.validateQuorum(), "ethereum 的指标关联了应急动作")
+}
+
+// TestCheckDeviation_QuorumReplace 测试偏差写入价格源自身指标时，只有该指标关联应急动作也会交叉验证参考价格源
+func TestCheckDeviation_QuorumReplace(t *testing.T) {
+	nodes := []*chainNode{{head: 100, l1Origin: 3000e8}, {head: 100, l1Origin: 3100e8}}
+	cfg := &config.Config{
+		Monitor:   config.MonitorConfig{PollInterval: 30},
+		Emergency: config.EmergencyConfig{Quorum: config.QuorumConfig{Min: 2}},
+	}
+	for i := 0; i < 3; i++ {
+		cfg.Ink.RpcURLs = append(cfg.Ink.RpcURLs, newChainNode(t, &chainNode{head: 100}))
+	}
+	for _, node := range nodes {
+		cfg.Ethereum.RpcURLs = append(cfg.Ethereum.RpcURLs, newChainNode(t, node))
+	}
+	cfg.InkRPC, cfg.EthRPC = cfg.Ink.RpcURLs[0], cfg.Ethereum.RpcURLs[0]
+	clientManager, err := client.NewClientManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(clientManager.Close)
+
+	// 规则只关联 chaos_push_oracle 自身的指标 oracle_price_spread
+	m := newTestMonitor(t, cfg)
+	m.clientManager = clientManager
+	m.emergency = newGuardingManager(t, cfg, config.AlertRuleConfig{
+		Name: "spread", Metric: metrics.GetMetricName("ink", "chaos_push_oracle"), Operator: ">", Threshold: 0.05, Actions: []string{"page"},
+	})
+	m.deviations = make(map[string]*deviationCheck)
+	feed := newFixedPrice("chaos_push_oracle", 3300, nil)
+	m.inkAccounts = []contracts.Account{feed}
+	require.NoError(t, m.buildDeviations())
+	check := m.deviations[metricKey("ink", "chaos_push_oracle")]
+	require.NotNil(t, check)
+	assert.True(t, m.guardsDeviation(check))
+
+	// 参考价格源所在的以太坊同样需要达到法定一致数
+	require.NoError(t, m.validateQuorum())
+	cfg.Emergency.Quorum.Min = 3
+	assert.ErrorContains(t, m.validateQuorum(), "ethereum 的指标关联了应急动作")
+	cfg.Emergency.Quorum.Min = 2
+
+	// 参考价格源在节点之间不一致时不参与中位数
+	check.references = []reference{{chain: "ethereum", account: contracts.NewPriceFeed("chainlink_eth_usd", common.HexToAddress("0x01"), "latestAnswer", 8)}}
+	_, err = m.checkDeviation(context.Background(), &snapshot{}, "ink", feed, 3300, true)
+	assert.ErrorContains(t, err, "多节点交叉验证失败")
+
+	// 不替换价格源指标的检查只看偏差指标的规则
+	check.replace = false
+	assert.False(t, m.guardsDeviation(check))
+}
+
+// TestMedian 测试中位数计算
+func TestMedian(t *testing.T) {
+	assert.Equal(t, 2.0, median([]float64{3, 1, 2}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+	assert.Equal(t, 7.0, median([]float64{7}))
+}
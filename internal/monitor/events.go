@@ -0,0 +1,195 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+)
+
+// pauseEvents 暂停类合约默认监听的事件，覆盖 OpenZeppelin Pausable 和 OP SuperchainConfig 的各版本
+var pauseEvents = []string{"Paused()", "Unpaused()", "Paused(address)", "Unpaused(address)", "Paused(string)"}
+
+// defaultEvents 按合约类型默认监听的事件
+var defaultEvents = map[string][]string{
+	contracts.TypePauseSimple:     pauseEvents,
+	contracts.TypePauseIdentifier: pauseEvents,
+	contracts.TypePriceFeed:       {"AnswerUpdated(int256,uint256,uint256)"},
+}
+
+// eventSignature 事件签名格式，如 Paused(address)
+var eventSignature = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\([A-Za-z0-9_,\[\]()]*\)$`)
+
+// eventWatch 合约触发立即检查的事件
+type eventWatch struct {
+	address common.Address
+	events  map[common.Hash]string // topic0 -> 事件签名
+}
+
+// newEventWatch 根据事件签名创建监听
+func newEventWatch(address common.Address, signatures []string) (*eventWatch, error) {
+	w := &eventWatch{address: address, events: make(map[common.Hash]string)}
+	for _, sig := range signatures {
+		sig = strings.ReplaceAll(sig, " ", "")
+		if !eventSignature.MatchString(sig) {
+			return nil, fmt.Errorf("事件签名格式错误: %q", sig)
+		}
+		w.events[crypto.Keccak256Hash([]byte(sig))] = sig
+	}
+	return w, nil
+}
+
+// matches 判断日志是否为监听的事件
+func (w *eventWatch) matches(log types.Log) bool {
+	if log.Address != w.address || len(log.Topics) == 0 {
+		return false
+	}
+	_, ok := w.events[log.Topics[0]]
+	return ok
+}
+
+// trigger 事件触发的检查
+type trigger struct {
+	chain string
+	log   *types.Log // nil 表示检查该链所有监听事件的合约（订阅重连后补偿）
+}
+
+// addEventWatch 根据合约配置登记事件监听，未配置 events 时按合约类型选择默认事件
+// Chainlink 价格源的代理合约不发出 AnswerUpdated，事件由背后的 aggregator 发出，
+// 因此 price_feed 只有配置了 event_address 才监听事件，未配置时只按轮询检查
+func (m *Monitor) addEventWatch(chain string, account contracts.Account, cfg *config.ContractConfig) error {
+	signatures := defaultEvents[account.Type()]
+	address := account.Address()
+	eventAddress := ""
+	if cfg != nil {
+		if len(cfg.Events) > 0 {
+			signatures = cfg.Events
+		}
+		eventAddress = cfg.EventAddress
+	}
+	if len(signatures) == 0 {
+		return nil
+	}
+	if account.Type() == contracts.TypePriceFeed && eventAddress == "" {
+		if cfg != nil && len(cfg.Events) > 0 {
+			return fmt.Errorf("%s.%s: price_feed 监听事件需要配置 event_address（代理合约背后的 aggregator 地址）", chain, account.Name())
+		}
+		m.logger.Info("价格源未配置 event_address，不监听事件，只按轮询检查",
+			zap.String("chain", chain),
+			zap.String("contract", account.Name()),
+		)
+		return nil
+	}
+	if eventAddress != "" {
+		address = common.HexToAddress(eventAddress)
+	}
+
+	w, err := newEventWatch(address, signatures)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", chain, account.Name(), err)
+	}
+	m.watches[metricKey(chain, account.Name())] = w
+	return nil
+}
+
+// eventQuery 返回链上所有监听事件的日志过滤条件
+func (m *Monitor) eventQuery(chain string) ethereum.FilterQuery {
+	var q ethereum.FilterQuery
+	addresses := make(map[common.Address]bool)
+	topics := make(map[common.Hash]bool)
+	var topic0 []common.Hash
+	for _, account := range m.chainAccounts(chain) {
+		w, ok := m.watches[metricKey(chain, account.Name())]
+		if !ok {
+			continue
+		}
+		if !addresses[w.address] {
+			addresses[w.address] = true
+			q.Addresses = append(q.Addresses, w.address)
+		}
+		for topic := range w.events {
+			if !topics[topic] {
+				topics[topic] = true
+				topic0 = append(topic0, topic)
+			}
+		}
+	}
+	if len(topic0) > 0 {
+		q.Topics = [][]common.Hash{topic0}
+	}
+	return q
+}
+
+// watchEvents 启动各链的事件监听，匹配的事件触发对应合约的立即检查
+// 配置了 ws_url 的链通过 eth_subscribe 订阅，其他链按区块范围轮询 eth_getLogs
+func (m *Monitor) watchEvents(ctx context.Context) {
+	events := m.cfg.Monitor.Events
+	for _, chain := range []string{"ethereum", "ink"} {
+		q := m.eventQuery(chain)
+		if len(q.Addresses) == 0 {
+			continue
+		}
+
+		handle := func(log types.Log) { m.onEvent(chain, log) }
+		logger := m.logger.With(zap.String("chain", chain))
+		if wsURL := m.chainConfig(chain).WsURL; wsURL != "" {
+			go client.SubscribeLogs(ctx, wsURL, q, handle, func() {
+				m.sendTrigger(trigger{chain: chain})
+			}, logger)
+		} else {
+			go m.chainClient(chain).PollLogs(ctx, q, events.GetPollInterval(), events.MaxBlockRange, handle)
+		}
+		logger.Info("启动事件监听", zap.Int("addresses", len(q.Addresses)), zap.Bool("websocket", m.chainConfig(chain).WsURL != ""))
+	}
+}
+
+// onEvent 处理监听到的日志
+func (m *Monitor) onEvent(chain string, log types.Log) {
+	event := ""
+	for _, account := range m.chainAccounts(chain) {
+		if w, ok := m.watches[metricKey(chain, account.Name())]; ok && w.matches(log) {
+			event = w.events[log.Topics[0]]
+			break
+		}
+	}
+	if event == "" {
+		return
+	}
+
+	m.logger.Info("检测到合约事件，立即检查",
+		zap.String("chain", chain),
+		zap.String("address", log.Address.Hex()),
+		zap.String("event", event),
+		zap.Uint64("block", log.BlockNumber),
+		zap.String("tx_hash", log.TxHash.Hex()),
+		zap.Bool("removed", log.Removed),
+	)
+	m.sendTrigger(trigger{chain: chain, log: &log})
+}
+
+// sendTrigger 通知调度器执行事件触发的检查，调度器繁忙时丢弃（定时轮询兜底）
+func (m *Monitor) sendTrigger(t trigger) {
+	select {
+	case m.triggers <- t:
+	default:
+		m.logger.Warn("事件触发队列已满，等待定时轮询检查", zap.String("chain", t.chain))
+	}
+}
+
+// chainConfig 返回链配置
+func (m *Monitor) chainConfig(chain string) *config.ChainConfig {
+	if chain == "ethereum" {
+		return &m.cfg.Ethereum
+	}
+	return &m.cfg.Ink
+}
@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+)
+
+// head 链的新区块
+type head struct {
+	chain  string
+	number uint64
+}
+
+// watchHeads 监听配置了 every_blocks 的链的新区块，驱动该链的检查
+// 配置了 ws_url 的链通过 eth_subscribe 订阅，其他链轮询 eth_blockNumber
+func (m *Monitor) watchHeads(ctx context.Context) {
+	for _, chain := range []string{"ethereum", "ink"} {
+		chainCfg := m.chainConfig(chain)
+		if chainCfg.EveryBlocks <= 0 {
+			continue
+		}
+
+		handle := func(number uint64) { m.onHead(chain, number) }
+		logger := m.logger.With(zap.String("chain", chain))
+		if chainCfg.WsURL != "" {
+			go client.SubscribeHeads(ctx, chainCfg.WsURL, handle, logger)
+		} else {
+			go m.chainClient(chain).PollHeads(ctx, chainCfg.GetHeadPollInterval(), handle)
+		}
+		logger.Info("按新区块驱动检查", zap.Int("every_blocks", chainCfg.EveryBlocks), zap.Bool("websocket", chainCfg.WsURL != ""))
+	}
+}
+
+// onHead 记录链的最新区块并通知调度器，调度器繁忙时丢弃（下一个区块会再次通知）
+func (m *Monitor) onHead(chain string, number uint64) {
+	m.metrics.SetChainHead(chain, number)
+	select {
+	case m.heads <- head{chain: chain, number: number}:
+	default:
+		m.logger.Debug("新区块通知队列已满，跳过", zap.String("chain", chain), zap.Uint64("block", number))
+	}
+}
@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// l1BlockAddress OP Stack L1Block 预部署合约地址，记录 L2 当前区块的 L1 来源区块
+const l1BlockAddress = "0x4200000000000000000000000000000000000015"
+
+// l1BlockNumberID L1Block.number() 的方法选择器
+var l1BlockNumberID = crypto.Keccak256([]byte("number()"))[:4]
+
+// checkLiveness 检查链本身是否正常出块，结果导出为指标并参与应急告警规则评估：
+// 最新区块时间戳距今的秒数、两次检查之间区块高度增加的数量、落后最高节点最多的节点的区块数，
+// 以及 INK 记录的 L1 来源区块落后 L1 最新区块的数量（排序器长时间不推进 L1 来源时增大）
+// 随RPC节点健康检查执行，同一时刻只有一个检查在运行
+func (m *Monitor) checkLiveness(ctx context.Context, health map[string][]client.EndpointHealth) {
+	now := time.Now()
+	headers := make(map[string]*types.Header)
+	for _, chain := range []string{"ethereum", "ink"} {
+		var lag uint64
+		for _, h := range health[chain] {
+			lag = max(lag, h.Lag)
+		}
+		m.reportLiveness(ctx, chain, metrics.ChainHeadLag, float64(lag))
+
+		header, err := m.chainClient(chain).HeaderByNumber(ctx, nil)
+		if err != nil {
+			m.logger.Warn("获取最新区块失败，跳过链存活检查", zap.String("chain", chain), zap.Error(err))
+			continue
+		}
+		headers[chain] = header
+		number := header.Number.Uint64()
+		m.metrics.SetChainHead(chain, number)
+
+		age := max(now.Sub(time.Unix(int64(header.Time), 0)).Seconds(), 0)
+		m.reportLiveness(ctx, chain, metrics.ChainHeadAge, age)
+
+		if prev, ok := m.lastHeads[chain]; ok {
+			var advance uint64
+			if number > prev {
+				advance = number - prev
+			}
+			m.reportLiveness(ctx, chain, metrics.ChainHeadAdvance, float64(advance))
+		}
+		m.lastHeads[chain] = number
+
+		m.logger.Debug("链存活检查",
+			zap.String("chain", chain),
+			zap.Uint64("block", number),
+			zap.Float64("head_age_seconds", age),
+			zap.Uint64("head_lag_blocks", lag),
+		)
+	}
+
+	l1, l2 := headers["ethereum"], headers["ink"]
+	if l1 == nil || l2 == nil {
+		return
+	}
+	origin, err := m.chainClient("ink").AtBlock(l2.Number).CallUint256(ctx, l1BlockAddress, l1BlockNumberID)
+	if err != nil {
+		m.logger.Warn("读取INK的L1来源区块失败", zap.Uint64("block", l2.Number.Uint64()), zap.Error(err))
+		return
+	}
+	var originLag uint64
+	if l1.Number.Cmp(origin) > 0 {
+		originLag = new(big.Int).Sub(l1.Number, origin).Uint64()
+	}
+	m.reportLiveness(ctx, "ink", metrics.ChainL1OriginLag, float64(originLag))
+}
+
+// reportLiveness 导出链存活检查指标并评估应急告警规则
+func (m *Monitor) reportLiveness(ctx context.Context, chain, check string, value float64) {
+	m.metrics.SetChainMetric(chain, check, value)
+	if err := m.emergency.CheckAlert(context.WithoutCancel(ctx), metrics.GetChainMetricName(chain, check), value); err != nil {
+		m.logger.Error("应急响应执行失败",
+			zap.String("chain", chain),
+			zap.String("check", check),
+			zap.Error(err),
+		)
+	}
+}
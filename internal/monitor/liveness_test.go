@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/emergency"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// chainNode 模拟链节点，返回固定的最新区块，eth_call 返回 l1Origin（L1Block.number）并记录调用的区块
+type chainNode struct {
+	head     uint64
+	time     time.Time
+	l1Origin uint64
+
+	mu       sync.Mutex
+	calledAt interface{}
+}
+
+func (n *chainNode) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(n.head)
+}
+
+func (n *chainNode) GetBlockByNumber(number string, full bool) *types.Header {
+	return &types.Header{
+		Number:     new(big.Int).SetUint64(n.head),
+		Time:       uint64(n.time.Unix()),
+		Difficulty: big.NewInt(0),
+	}
+}
+
+func (n *chainNode) Call(args map[string]interface{}, block interface{}) hexutil.Bytes {
+	n.mu.Lock()
+	n.calledAt = block
+	n.mu.Unlock()
+	return common.BigToHash(new(big.Int).SetUint64(n.l1Origin)).Bytes()
+}
+
+func newChainNode(t *testing.T, node *chainNode) string {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL
+}
+
+// TestCheckLiveness 测试链存活检查的指标值参与告警规则评估
+func TestCheckLiveness(t *testing.T) {
+	now := time.Now()
+	l1 := &chainNode{head: 1000, time: now.Add(-5 * time.Second)}
+	l2 := &chainNode{head: 500, time: now.Add(-2 * time.Minute), l1Origin: 990}
+	cfg := &config.Config{
+		EthRPC:  newChainNode(t, l1),
+		InkRPC:  newChainNode(t, l2),
+		Monitor: config.MonitorConfig{PollInterval: 30},
+		Emergency: config.EmergencyConfig{Rules: []config.AlertRuleConfig{
+			{Name: "ink_stalled", Metric: "ink_eth_monitor_ink_head_*", Operator: ">=", Threshold: 0},
+			{Name: "ink_l1_origin", Metric: "ink_eth_monitor_ink_l1_origin_lag_blocks", Operator: ">", Threshold: 5},
+		}},
+	}
+
+	core, logs := observer.New(zap.WarnLevel)
+	logger := zap.New(core)
+	clientManager, err := client.NewClientManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(clientManager.Close)
+	metricsManager := metrics.NewMetrics(&cfg.Prometheus, logger)
+	emergencyManager, err := emergency.NewManager(context.Background(), &cfg.Emergency, "", metricsManager, logger)
+	require.NoError(t, err)
+
+	m := newTestMonitor(t, cfg)
+	m.clientManager, m.emergency, m.logger = clientManager, emergencyManager, logger
+	m.lastHeads = make(map[string]uint64)
+
+	matched := func() map[string]float64 {
+		values := make(map[string]float64)
+		for _, entry := range logs.TakeAll() {
+			if entry.Message == "告警规则命中" {
+				fields := entry.ContextMap()
+				values[fields["metric"].(string)] = fields["value"].(float64)
+			}
+		}
+		return values
+	}
+
+	m.checkLiveness(context.Background(), nil)
+	values := matched()
+	assert.InDelta(t, 120, values[metrics.GetChainMetricName("ink", metrics.ChainHeadAge)], 5)
+	assert.Equal(t, 10.0, values[metrics.GetChainMetricName("ink", metrics.ChainL1OriginLag)])
+	assert.Equal(t, 0.0, values[metrics.GetChainMetricName("ink", metrics.ChainHeadLag)])
+	assert.NotContains(t, values, metrics.GetChainMetricName("ink", metrics.ChainHeadAdvance))
+
+	// 第二次检查开始导出区块高度的推进数量，INK 停止出块时为 0
+	l1.head = 1003
+	m.checkLiveness(context.Background(), map[string][]client.EndpointHealth{"ink": {{Lag: 2}, {Lag: 7}}})
+	values = matched()
+	assert.Equal(t, 0.0, values[metrics.GetChainMetricName("ink", metrics.ChainHeadAdvance)])
+	assert.Equal(t, 7.0, values[metrics.GetChainMetricName("ink", metrics.ChainHeadLag)])
+	assert.Equal(t, 13.0, values[metrics.GetChainMetricName("ink", metrics.ChainL1OriginLag)])
+	assert.Equal(t, uint64(1003), m.lastHeads["ethereum"])
+}
@@ -3,6 +3,7 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -26,6 +27,16 @@ type Monitor struct {
 	stopChan      chan struct{}
 	ethAccounts   []contracts.Account
 	inkAccounts   []contracts.Account
+	alerts        map[string]*config.AlertConfig // 按 chain_name 索引的合约告警配置
+	intervals     map[string]checkInterval       // 按 chain_name 索引的单独配置的检查间隔
+	slots         chan struct{}                  // 同时执行的合约检查槽位
+	watches       map[string]*eventWatch         // 按 chain_name 索引的触发立即检查的事件
+	triggers      chan trigger                   // 事件触发的检查
+	heads         chan head                      // 驱动检查的新区块
+	lastHeads     map[string]uint64              // 各链上一次存活检查的区块高度
+	deviations    map[string]*deviationCheck     // 按价格源 chain_name 索引的价格偏差检查
+	status        statusTracker                  // 各合约最近一次检查的结果
+	onFailure     map[string]string              // 按 chain_name 索引的单独配置的检查失败处理方式
 }
 
 // NewMonitor 创建监控器
@@ -35,7 +46,221 @@ func NewMonitor(
 	metricsManager *metrics.Metrics,
 	emergencyManager *emergency.Manager,
 	logger *zap.Logger,
-) *Monitor {
+) (*Monitor, error) {
+	m := &Monitor{
+		cfg:           cfg,
+		clientManager: clientManager,
+		metrics:       metricsManager,
+		emergency:     emergencyManager,
+		logger:        logger,
+		stopChan:      make(chan struct{}),
+		alerts:        make(map[string]*config.AlertConfig),
+		intervals:     make(map[string]checkInterval),
+		slots:         make(chan struct{}, cfg.Monitor.GetConcurrency()),
+		watches:       make(map[string]*eventWatch),
+		triggers:      make(chan trigger, 64),
+		heads:         make(chan head, 16),
+		lastHeads:     make(map[string]uint64),
+		deviations:    make(map[string]*deviationCheck),
+		onFailure:     make(map[string]string),
+	}
+
+	// 未配置任何合约时使用内置的默认监控项
+	if len(cfg.Ethereum.Contracts) == 0 && len(cfg.Ink.Contracts) == 0 {
+		m.ethAccounts, m.inkAccounts = defaultAccounts(cfg)
+		if cfg.Monitor.Events.Enabled {
+			for _, chain := range []string{"ethereum", "ink"} {
+				for _, account := range m.chainAccounts(chain) {
+					if err := m.addEventWatch(chain, account, nil); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		if err := m.buildDeviations(); err != nil {
+			return nil, err
+		}
+		if err := m.validateMetricNames(); err != nil {
+			return nil, err
+		}
+		if err := m.validateQuorum(); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	var err error
+	if m.ethAccounts, err = m.buildAccounts("ethereum", cfg.Ethereum.Contracts); err != nil {
+		return nil, err
+	}
+	if m.inkAccounts, err = m.buildAccounts("ink", cfg.Ink.Contracts); err != nil {
+		return nil, err
+	}
+
+	// 校验 price_diff 告警引用的合约是否存在
+	for key, alert := range m.alerts {
+		if alert.Type == config.AlertTypePriceDiff {
+			if _, _, ok := m.findAccount(alert.CompareWith); !ok {
+				return nil, fmt.Errorf("%s 的告警引用了不存在的合约: %s", key, alert.CompareWith)
+			}
+		}
+	}
+
+	if err := m.buildDeviations(); err != nil {
+		return nil, err
+	}
+	if err := m.validateMetricNames(); err != nil {
+		return nil, err
+	}
+	if err := m.validateQuorum(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// validateMetricNames 校验合约指标、附加指标、链存活指标和价格偏差指标的名称互不重复
+// 指标名称由合约名称拼接而成，合约名称与其他指标重名时指标无法注册
+func (m *Monitor) validateMetricNames() error {
+	owners := make(map[string]string)
+	claim := func(name, owner string) error {
+		if other, exists := owners[name]; exists {
+			return fmt.Errorf("指标名称冲突: %s 与 %s 都导出 %s，请修改合约名称", other, owner, name)
+		}
+		owners[name] = owner
+		return nil
+	}
+
+	for _, chain := range []string{"ethereum", "ink"} {
+		for _, check := range metrics.ChainChecks(chain) {
+			if err := claim(metrics.GetChainMetricName(chain, check), fmt.Sprintf("%s 链存活检查 %s", chain, check)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, chain := range []string{"ethereum", "ink"} {
+		for _, account := range m.chainAccounts(chain) {
+			if err := claim(metrics.GetMetricName(chain, account.Name()), fmt.Sprintf("%s 合约 %s", chain, account.Name())); err != nil {
+				return err
+			}
+			detailed, ok := account.(contracts.DetailedAccount)
+			if !ok {
+				continue
+			}
+			for _, detail := range detailed.DetailNames() {
+				owner := fmt.Sprintf("%s 合约 %s 的附加指标 %s", chain, account.Name(), detail)
+				if err := claim(metrics.GetDetailMetricName(chain, account.Name(), detail), owner); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, check := range m.deviations {
+		if err := claim(metrics.GetPriceDeviationMetricName(check.asset), "价格偏差 "+check.asset); err != nil {
+			return err
+		}
+		if err := claim(metrics.GetPriceReferenceMetricName(check.asset), "参考价格 "+check.asset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onFailureOf 返回合约检查失败时指标的处理方式，未单独配置时使用 monitor.on_failure
+func (m *Monitor) onFailureOf(chain, contractName string) string {
+	if policy, ok := m.onFailure[metricKey(chain, contractName)]; ok {
+		return policy
+	}
+	return m.cfg.Monitor.GetOnFailure()
+}
+
+// validateQuorum 校验关联了应急动作的链是否配置了足够的RPC节点
+// 合约的指标值、附加指标、价格偏差指标及 price_diff 的对比价格源都计入；链存活指标不进行交叉验证，由应急响应配置限制只能关联通知动作
+func (m *Monitor) validateQuorum() error {
+	q := m.cfg.Emergency.Quorum
+	if q.Min <= 0 {
+		return nil
+	}
+	if q.Providers > 0 && q.Providers < q.Min {
+		return fmt.Errorf("emergency.quorum.providers (%d) 不能小于 min (%d)", q.Providers, q.Min)
+	}
+	if q.Tolerance < 0 {
+		return fmt.Errorf("emergency.quorum.tolerance 不能为负数: %v", q.Tolerance)
+	}
+
+	// 价格偏差指标由价格源和参考价格源共同计算，price_diff 的指标值由合约和对比价格源共同计算，所在的链都需要交叉验证
+	guarded := make(map[string]bool)
+	for chain, accounts := range map[string][]contracts.Account{"ethereum": m.ethAccounts, "ink": m.inkAccounts} {
+		for _, account := range accounts {
+			if m.guardsAccount(chain, account) {
+				guarded[chain] = true
+			}
+			alert, ok := m.alerts[metricKey(chain, account.Name())]
+			if !ok || alert.Type != config.AlertTypePriceDiff || !m.emergency.Guards(metrics.GetMetricName(chain, account.Name())) {
+				continue
+			}
+			if compareChain, _, ok := m.findAccount(alert.CompareWith); ok {
+				guarded[compareChain] = true
+			}
+		}
+	}
+	for _, check := range m.deviations {
+		if !m.guardsDeviation(check) {
+			continue
+		}
+		guarded[check.chain] = true
+		for _, ref := range check.references {
+			guarded[ref.chain] = true
+		}
+	}
+	for _, chain := range []string{"ethereum", "ink"} {
+		if !guarded[chain] {
+			continue
+		}
+		if endpoints := len(m.chainClient(chain).Endpoints()); endpoints < q.Min {
+			return fmt.Errorf("%s 的指标关联了应急动作，但只配置了 %d 个RPC节点，少于 emergency.quorum.min (%d)", chain, endpoints, q.Min)
+		}
+	}
+	return nil
+}
+
+// buildAccounts 根据配置创建链上的监控合约
+func (m *Monitor) buildAccounts(chain string, contractCfgs []config.ContractConfig) ([]contracts.Account, error) {
+	accounts := make([]contracts.Account, 0, len(contractCfgs))
+	for _, contractCfg := range contractCfgs {
+		account, err := contracts.NewAccount(contractCfg)
+		if err != nil {
+			return nil, fmt.Errorf("创建%s合约监控失败: %w", chain, err)
+		}
+		accounts = append(accounts, account)
+		if m.cfg.Monitor.Events.Enabled {
+			if err := m.addEventWatch(chain, account, &contractCfg); err != nil {
+				return nil, fmt.Errorf("创建%s合约事件监听失败: %w", chain, err)
+			}
+		}
+		if contractCfg.Alert != nil {
+			m.alerts[metricKey(chain, contractCfg.Name)] = contractCfg.Alert
+		}
+		if contractCfg.OnFailure != "" {
+			m.onFailure[metricKey(chain, contractCfg.Name)] = contractCfg.OnFailure
+		}
+		if contractCfg.PollInterval > 0 || contractCfg.Jitter > 0 {
+			m.intervals[metricKey(chain, contractCfg.Name)] = checkInterval{
+				interval: contractCfg.GetPollInterval(m.cfg.Monitor.GetPollDuration()),
+				jitter:   contractCfg.GetJitter(m.cfg.Monitor.GetJitter()),
+			}
+		}
+		m.logger.Info("加载合约监控配置",
+			zap.String("chain", chain),
+			zap.String("name", contractCfg.Name),
+			zap.String("type", contractCfg.Type),
+			zap.String("address", contractCfg.Address),
+		)
+	}
+	return accounts, nil
+}
+
+// defaultAccounts 返回内置的默认监控合约
+func defaultAccounts(cfg *config.Config) (ethAccounts, inkAccounts []contracts.Account) {
 	// 使用配置中的地址，如果未配置则使用默认值
 	l1SuperChainConfig := getAddressOrDefault(cfg.Contracts.L1.SuperChainConfig, contracts.DefaultL1SuperChainConfig)
 	l1StandardBridge := getAddressOrDefault(cfg.Contracts.L1.StandardBridge, contracts.DefaultL1StandardBridge)
@@ -44,24 +269,30 @@ func NewMonitor(
 	l2ChaosPushOracle := getAddressOrDefault(cfg.Contracts.L2.ChaosPushOracle, contracts.DefaultL2ChaosPushOracle)
 	l2VariableDebtInkWlWETH := getAddressOrDefault(cfg.Contracts.L2.VariableDebtInkWlWETH, contracts.DefaultL2VariableDebtInkWlWETH)
 
-	return &Monitor{
-		cfg:           cfg,
-		clientManager: clientManager,
-		metrics:       metricsManager,
-		emergency:     emergencyManager,
-		logger:        logger,
-		stopChan:      make(chan struct{}),
-		ethAccounts: []contracts.Account{
-			contracts.NewSuperChainConfig(common.HexToAddress(l1SuperChainConfig)),
-			contracts.NewInkOptimismPortal(common.HexToAddress(l1InkOptimismPortal)),
-			contracts.NewInkStandardBridge(common.HexToAddress(l1StandardBridge)),
-		},
-		inkAccounts: []contracts.Account{
-			contracts.NewAAveProtocolDataProvider(common.HexToAddress(l2AaveProtocolDataProvider)),
-			contracts.NewChaosPushOracle(common.HexToAddress(l2ChaosPushOracle)),
-			contracts.NewInkWLWEth(common.HexToAddress(l2VariableDebtInkWlWETH)),
-		},
+	ethAccounts = []contracts.Account{
+		contracts.NewSuperChainConfig(common.HexToAddress(l1SuperChainConfig)),
+		contracts.NewInkOptimismPortal(common.HexToAddress(l1InkOptimismPortal)),
+		contracts.NewInkStandardBridge(common.HexToAddress(l1StandardBridge)),
 	}
+	inkAccounts = []contracts.Account{
+		contracts.NewAAveProtocolDataProvider(common.HexToAddress(l2AaveProtocolDataProvider)),
+		contracts.NewChaosPushOracle(common.HexToAddress(l2ChaosPushOracle)),
+		contracts.NewInkWLWEth(common.HexToAddress(l2VariableDebtInkWlWETH)),
+	}
+	return ethAccounts, inkAccounts
+}
+
+// chainAccounts 返回链上的监控合约
+func (m *Monitor) chainAccounts(chain string) []contracts.Account {
+	if chain == "ethereum" {
+		return m.ethAccounts
+	}
+	return m.inkAccounts
+}
+
+// metricKey 返回链和合约名称组成的唯一键
+func metricKey(chain, contractName string) string {
+	return fmt.Sprintf("%s_%s", chain, contractName)
 }
 
 // getAddressOrDefault 返回配置的地址，如果为空则返回默认值
@@ -79,25 +310,19 @@ func (m *Monitor) Start(ctx context.Context) error {
 	// 注册所有指标
 	m.registerMetrics()
 
-	// 启动轮询
-	ticker := time.NewTicker(m.cfg.Monitor.GetPollDuration())
-	defer ticker.Stop()
-
-	// 立即执行一次
-	m.pollAll(ctx)
+	// 检查RPC节点健康状态，不健康的节点在调用中排在最后
+	m.checkEndpoints(ctx)
 
-	for {
-		select {
-		case <-ctx.Done():
-			m.logger.Info("监控服务收到停止信号")
-			return ctx.Err()
-		case <-m.stopChan:
-			m.logger.Info("监控服务停止")
-			return nil
-		case <-ticker.C:
-			m.pollAll(ctx)
-		}
+	// 监听合约事件，匹配的事件触发立即检查，定时轮询仍然保留
+	if m.cfg.Monitor.Events.Enabled {
+		m.watchEvents(ctx)
 	}
+
+	// 配置了 every_blocks 的链按新区块驱动检查
+	m.watchHeads(ctx)
+
+	// 按每个合约的检查间隔调度，所有检查立即执行一次
+	return m.schedule(ctx)
 }
 
 // Stop 停止监控
@@ -120,171 +345,304 @@ func (m *Monitor) registerMetrics() {
 	m.logger.Info("完成指标注册")
 }
 
-// pollAll 轮询所有合约
-func (m *Monitor) pollAll(ctx context.Context) {
-	m.logger.Debug("开始轮询所有合约")
+// checkTask 一次合约检查
+type checkTask struct {
+	chain    string
+	contract contracts.Account
+	done     func() // 检查完成后调用，可以为 nil
+}
 
-	// 轮询Ethereum合约
-	for _, contract := range m.ethAccounts {
-		m.pollEthereumContract(ctx, contract)
+// runChecks 使用有限的并发数执行合约检查，等待全部完成
+// 关联了应急规则的检查排在前面，优先获得执行槽位；每个检查有独立的超时时间，
+// 单个合约卡住不会影响其他合约的检查和应急响应。并发数在同时执行的多批检查之间共享
+func (m *Monitor) runChecks(ctx context.Context, snap *snapshot, tasks []checkTask) {
+	var guarded, others []checkTask
+	for _, task := range tasks {
+		if m.emergency.Guards(metrics.GetMetricName(task.chain, task.contract.Name())) {
+			guarded = append(guarded, task)
+		} else {
+			others = append(others, task)
+		}
 	}
 
-	// 轮询INK合约
-	for _, contract := range m.inkAccounts {
-		m.pollInkContract(ctx, contract)
+	var wg sync.WaitGroup
+	for i, task := range append(guarded, others...) {
+		select {
+		case m.slots <- struct{}{}:
+		case <-ctx.Done():
+			for _, skipped := range append(guarded, others...)[i:] {
+				if skipped.done != nil {
+					skipped.done()
+				}
+			}
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(task checkTask) {
+			defer wg.Done()
+			defer func() { <-m.slots }()
+			if task.done != nil {
+				defer task.done()
+			}
+			if task.chain == "ethereum" {
+				m.pollEthereumContract(ctx, snap, task.contract)
+			} else {
+				m.pollInkContract(ctx, snap, task.contract)
+			}
+		}(task)
 	}
+	wg.Wait()
+}
 
-	// 推送指标到Prometheus Gateway
-	if err := m.metrics.Push(); err != nil {
-		m.logger.Error("推送指标失败", zap.Error(err))
+// checkEndpoints 检查RPC节点健康状态和链存活状态并导出指标
+func (m *Monitor) checkEndpoints(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Monitor.GetCheckTimeout())
+	defer cancel()
+
+	m.clientManager.CheckHealth(ctx)
+	health := m.clientManager.EndpointHealth()
+	for chain, endpoints := range health {
+		for _, h := range endpoints {
+			m.metrics.SetEndpointHealth(chain, h.Endpoint, h.Healthy, h.Lag, h.Latency, h.ErrorRate)
+			m.metrics.SetChainHead(chain, h.Head)
+		}
 	}
+	m.checkLiveness(ctx, health)
 }
 
-// pollEthereumContract 轮询Ethereum合约
-func (m *Monitor) pollEthereumContract(ctx context.Context, contract contracts.Account) {
-	err := retry.Do(ctx, func() error {
-		return m.checkEthereumContract(ctx, contract)
-	}, m.cfg.Monitor.RetryTimes, m.cfg.Monitor.GetRetryDelay(), m.logger)
+// pollEthereumContract 轮询Ethereum合约，包括重试在内不超过 check_timeout
+func (m *Monitor) pollEthereumContract(ctx context.Context, snap *snapshot, contract contracts.Account) {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Monitor.GetCheckTimeout())
+	defer cancel()
+
+	start := time.Now()
+	err := retry.DoNotify(ctx, func() error {
+		return m.checkEthereumContract(ctx, snap, contract)
+	}, m.cfg.Monitor.RetryTimes, m.cfg.Monitor.GetRetryDelay(), m.logger, func(int, error) {
+		m.metrics.IncCheckRetry("ethereum", contract.Name())
+	})
+	m.metrics.ObserveCheck("ethereum", contract.Name(), time.Since(start))
 
 	if err != nil {
+		m.status.failure("ethereum", contract, err)
+		m.metrics.SetContractUp("ethereum", contract.Name(), false)
+		m.metrics.MarkContractStale("ethereum", contract.Name(), m.onFailureOf("ethereum", contract.Name()))
 		m.logger.Error("检查Ethereum合约失败",
 			zap.String("contract", contract.Address().Hex()),
 			zap.String("name", contract.Name()),
+			zap.Uint64("block", snap.block("ethereum")),
 			zap.Error(err),
 		)
 	}
 }
 
-// pollInkContract 轮询INK合约
-func (m *Monitor) pollInkContract(ctx context.Context, contract contracts.Account) {
-	err := retry.Do(ctx, func() error {
-		return m.checkInkContract(ctx, contract)
-	}, m.cfg.Monitor.RetryTimes, m.cfg.Monitor.GetRetryDelay(), m.logger)
+// pollInkContract 轮询INK合约，包括重试在内不超过 check_timeout
+func (m *Monitor) pollInkContract(ctx context.Context, snap *snapshot, contract contracts.Account) {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Monitor.GetCheckTimeout())
+	defer cancel()
+
+	start := time.Now()
+	err := retry.DoNotify(ctx, func() error {
+		return m.checkInkContract(ctx, snap, contract)
+	}, m.cfg.Monitor.RetryTimes, m.cfg.Monitor.GetRetryDelay(), m.logger, func(int, error) {
+		m.metrics.IncCheckRetry("ink", contract.Name())
+	})
+	m.metrics.ObserveCheck("ink", contract.Name(), time.Since(start))
 
 	if err != nil {
+		m.status.failure("ink", contract, err)
+		m.metrics.SetContractUp("ink", contract.Name(), false)
+		m.metrics.MarkContractStale("ink", contract.Name(), m.onFailureOf("ink", contract.Name()))
 		m.logger.Error("检查INK合约失败",
 			zap.String("contract", contract.Address().Hex()),
 			zap.String("name", contract.Name()),
+			zap.Uint64("block", snap.block("ink")),
 			zap.Error(err),
 		)
 	}
 }
 
 // checkEthereumContract 检查Ethereum合约
-func (m *Monitor) checkEthereumContract(ctx context.Context, contract contracts.Account) error {
+func (m *Monitor) checkEthereumContract(ctx context.Context, snap *snapshot, contract contracts.Account) error {
 	// 调用合约的Monitor方法获取指标值
-	value, err := contract.Monitor(ctx, m.clientManager.GetEthereumClient())
+	reading, trusted, err := m.readContract(ctx, snap, "ethereum", contract)
 	if err != nil {
 		return fmt.Errorf("监控合约失败: %w", err)
 	}
+	value := reading.Value
+
+	// 检查合约配置的告警条件
+	value, err = m.evaluateAlert(ctx, snap, "ethereum", contract, value)
+	if err != nil {
+		return fmt.Errorf("检查告警条件失败: %w", err)
+	}
+
+	// 与参考价格源比较价格偏差
+	value, err = m.checkDeviation(ctx, snap, "ethereum", contract, value, trusted)
+	if err != nil {
+		return fmt.Errorf("检查价格偏差失败: %w", err)
+	}
 
 	// 获取指标名称
 	metricName := metrics.GetMetricName("ethereum", contract.Name())
 
-	// 设置指标值
+	// 设置指标值及读取的区块
 	m.metrics.SetContractMetric("ethereum", contract.Name(), value)
+	m.metrics.SetContractBlock("ethereum", contract.Name(), snap.block("ethereum"))
+	m.metrics.SetContractUpdated("ethereum", contract.Name())
+	m.metrics.SetContractUp("ethereum", contract.Name(), true)
+	m.status.success("ethereum", contract, value, snap.block("ethereum"))
 
 	// 检查是否触发应急响应
-	if err := m.emergency.CheckAlert(metricName, value); err != nil {
-		m.logger.Error("应急响应执行失败",
-			zap.String("contract", contract.Name()),
-			zap.Error(err),
-		)
-	}
+	m.checkEmergency(ctx, contract, metricName, value, trusted, snap.block("ethereum"))
+	m.checkDetails(ctx, snap, "ethereum", contract, reading.Details, trusted)
 
 	m.logger.Info("检查Ethereum合约",
 		zap.String("contract", contract.Name()),
 		zap.String("type", contract.Type()),
 		zap.Float64("value", value),
+		zap.Uint64("block", snap.block("ethereum")),
 	)
 
 	return nil
 }
 
 // checkInkContract 检查INK合约
-func (m *Monitor) checkInkContract(ctx context.Context, contract contracts.Account) error {
-	// 特殊处理：ChaosPushOracle 需要跨链价格比较
-	if contract.Type() == contracts.TypePriceFeed && contract.Name() == "chaos_push_oracle" {
-		return m.checkPriceFeedDeviation(ctx, contract)
+func (m *Monitor) checkInkContract(ctx context.Context, snap *snapshot, contract contracts.Account) error {
+	reading, trusted, err := m.readContract(ctx, snap, "ink", contract)
+	if err != nil {
+		return fmt.Errorf("监控合约失败: %w", err)
 	}
+	value := reading.Value
 
-	// 其他合约正常处理
-	value, err := contract.Monitor(ctx, m.clientManager.GetInkClient())
+	// 检查合约配置的告警条件
+	value, err = m.evaluateAlert(ctx, snap, "ink", contract, value)
 	if err != nil {
-		return fmt.Errorf("监控合约失败: %w", err)
+		return fmt.Errorf("检查告警条件失败: %w", err)
+	}
+
+	// 与参考价格源比较价格偏差
+	value, err = m.checkDeviation(ctx, snap, "ink", contract, value, trusted)
+	if err != nil {
+		return fmt.Errorf("检查价格偏差失败: %w", err)
 	}
 
 	// 获取指标名称
 	metricName := metrics.GetMetricName("ink", contract.Name())
 
-	// 设置指标值
+	// 设置指标值及读取的区块
 	m.metrics.SetContractMetric("ink", contract.Name(), value)
+	m.metrics.SetContractBlock("ink", contract.Name(), snap.block("ink"))
+	m.metrics.SetContractUpdated("ink", contract.Name())
+	m.metrics.SetContractUp("ink", contract.Name(), true)
+	m.status.success("ink", contract, value, snap.block("ink"))
 
 	// 检查是否触发应急响应
-	if err := m.emergency.CheckAlert(metricName, value); err != nil {
-		m.logger.Error("应急响应执行失败",
-			zap.String("contract", contract.Name()),
-			zap.Error(err),
-		)
-	}
+	m.checkEmergency(ctx, contract, metricName, value, trusted, snap.block("ink"))
+	m.checkDetails(ctx, snap, "ink", contract, reading.Details, trusted)
 
 	m.logger.Info("检查INK合约",
 		zap.String("contract", contract.Name()),
 		zap.String("type", contract.Type()),
 		zap.Float64("value", value),
+		zap.Uint64("block", snap.block("ink")),
 	)
 
 	return nil
 }
 
-// checkPriceFeedDeviation 检查价格源偏差（跨链比较）
-func (m *Monitor) checkPriceFeedDeviation(ctx context.Context, contract contracts.Account) error {
-	// 1. 获取 INK 链上的价格
-	inkPrice, err := contract.Monitor(ctx, m.clientManager.GetInkClient())
+// readContract 读取合约指标值和附加指标
+// 指标值、任一附加指标或价格偏差指标关联了应急动作时，在配置了 emergency.quorum 的情况下进行多节点交叉验证（附加指标一并比较），
+// 未达到法定一致数时 trusted 为 false，此时仍返回首选节点的结果用于指标展示
+func (m *Monitor) readContract(ctx context.Context, snap *snapshot, chain string, contract contracts.Account) (reading *contracts.Reading, trusted bool, err error) {
+	reading, err = m.monitorContract(ctx, snap, chain, contract)
 	if err != nil {
-		return fmt.Errorf("获取INK链价格失败: %w", err)
+		return nil, false, err
+	}
+	if m.cfg.Emergency.Quorum.Min <= 0 || !m.guardsAccount(chain, contract) {
+		return reading, true, nil
 	}
 
-	// 2. 创建以太坊主网 Chainlink ETH/USD 预言机实例
-	chainlinkAddr := common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419")
-	chainlink := contracts.NewChaosPushOracle(chainlinkAddr)
-
-	// 3. 获取以太坊主网的价格
-	ethPrice, err := chainlink.Monitor(ctx, m.clientManager.GetEthereumClient())
+	agreed, err := m.quorumRead(ctx, snap, chain, contract)
 	if err != nil {
-		return fmt.Errorf("获取ETH主网价格失败: %w", err)
+		m.logger.Error("多节点交叉验证失败，本轮不执行应急响应",
+			zap.String("chain", chain),
+			zap.String("contract", contract.Name()),
+			zap.Float64("value", reading.Value),
+			zap.Uint64("block", snap.block(chain)),
+			zap.Error(err),
+		)
+		return reading, false, nil
 	}
+	return agreed, true, nil
+}
 
-	// 4. 计算价格偏差（绝对值）
-	var deviation float64
-	if ethPrice != 0 {
-		deviation = (inkPrice - ethPrice) / ethPrice
-		if deviation < 0 {
-			deviation = -deviation // 取绝对值
+// guardsAccount 检查合约的指标值、附加指标或以其为价格源的偏差指标是否关联了应急动作
+func (m *Monitor) guardsAccount(chain string, contract contracts.Account) bool {
+	if m.emergency.Guards(metrics.GetMetricName(chain, contract.Name())) {
+		return true
+	}
+	if check, ok := m.deviations[metricKey(chain, contract.Name())]; ok && m.guardsDeviation(check) {
+		return true
+	}
+	if detailed, ok := contract.(contracts.DetailedAccount); ok {
+		for _, detail := range detailed.DetailNames() {
+			if m.emergency.Guards(metrics.GetDetailMetricName(chain, contract.Name(), detail)) {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	// 获取指标名称
-	metricName := metrics.GetMetricName("ink", contract.Name())
+// monitorContract 从首选节点读取合约指标值
+// 提供附加指标的合约（如 latestRoundData 价格源）同时返回附加指标，读取结果存在的问题记录警告日志
+func (m *Monitor) monitorContract(ctx context.Context, snap *snapshot, chain string, contract contracts.Account) (*contracts.Reading, error) {
+	detailed, ok := contract.(contracts.DetailedAccount)
+	if !ok {
+		value, err := contract.Monitor(ctx, snap.caller(chain))
+		if err != nil {
+			return nil, err
+		}
+		return &contracts.Reading{Value: value}, nil
+	}
 
-	// 5. 推送实际偏差值（如 0.03 表示 3% 偏差）
-	m.metrics.SetContractMetric("ink", contract.Name(), deviation)
+	reading, err := detailed.Read(ctx, snap.caller(chain))
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range reading.Warnings {
+		m.logger.Warn("合约读取结果异常",
+			zap.String("chain", chain),
+			zap.String("contract", contract.Name()),
+			zap.Float64("value", reading.Value),
+			zap.Uint64("block", snap.block(chain)),
+			zap.String("warning", warning),
+		)
+	}
+	return reading, nil
+}
 
-	// 检查是否触发应急响应
-	if err := m.emergency.CheckAlert(metricName, deviation); err != nil {
+// checkDetails 导出附加指标并评估告警规则，在检查成功后调用，重试不会重复计入规则的连续命中次数
+func (m *Monitor) checkDetails(ctx context.Context, snap *snapshot, chain string, contract contracts.Account, details map[string]float64, trusted bool) {
+	for detail, value := range details {
+		m.metrics.SetContractDetail(chain, contract.Name(), detail, value)
+		m.checkEmergency(ctx, contract, metrics.GetDetailMetricName(chain, contract.Name(), detail), value, trusted, snap.block(chain))
+	}
+}
+
+// checkEmergency 检查是否触发应急响应，未通过交叉验证的值不会触发
+// 应急交易的发送和回执等待不受单个检查的超时限制，由交易自身的回执超时约束
+func (m *Monitor) checkEmergency(ctx context.Context, contract contracts.Account, metricName string, value float64, trusted bool, block uint64) {
+	if !trusted {
+		return
+	}
+	if err := m.emergency.CheckAlert(context.WithoutCancel(ctx), metricName, value); err != nil {
 		m.logger.Error("应急响应执行失败",
 			zap.String("contract", contract.Name()),
+			zap.Uint64("block", block),
 			zap.Error(err),
 		)
 	}
-
-	m.logger.Info("检查价格源偏差",
-		zap.String("contract", contract.Name()),
-		zap.Float64("ink_price", inkPrice),
-		zap.Float64("eth_price", ethPrice),
-		zap.Float64("deviation", deviation),
-		zap.Float64("deviation_percent", deviation*100),
-	)
-
-	return nil
 }
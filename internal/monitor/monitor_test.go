@@ -0,0 +1,544 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/emergency"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// fakeAccount 测试用监控合约，hang 为 true 时一直阻塞到 ctx 取消，err 不为空时读取失败
+type fakeAccount struct {
+	contracts.BaseContract
+	hang  bool
+	err   error
+	calls atomic.Int32
+}
+
+func newFakeAccount(name string, hang bool) *fakeAccount {
+	return &fakeAccount{BaseContract: contracts.NewBaseContract(name, common.Address{}, "fake"), hang: hang}
+}
+
+func (a *fakeAccount) Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error) {
+	a.calls.Add(1)
+	if a.hang {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	if a.err != nil {
+		return 0, a.err
+	}
+	return 1, nil
+}
+
+// newTestMonitor 创建不连接RPC节点的监控器
+func newTestMonitor(t *testing.T, cfg *config.Config) *Monitor {
+	logger := zap.NewNop()
+	metricsManager := metrics.NewMetrics(&cfg.Prometheus, logger)
+	emergencyManager, err := emergency.NewManager(context.Background(), &cfg.Emergency, "", metricsManager, logger)
+	require.NoError(t, err)
+	return &Monitor{
+		cfg:       cfg,
+		metrics:   metricsManager,
+		emergency: emergencyManager,
+		logger:    logger,
+		alerts:    make(map[string]*config.AlertConfig),
+		intervals: make(map[string]checkInterval),
+		slots:     make(chan struct{}, cfg.Monitor.GetConcurrency()),
+	}
+}
+
+// argusNode 模拟 Argus 合约所在的节点，getAllDelegates 返回 bot 地址
+type argusNode struct {
+	bot common.Address
+}
+
+func (n *argusNode) Call(args map[string]interface{}, block interface{}) (hexutil.Bytes, error) {
+	addresses, err := abi.NewType("address[]", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return abi.Arguments{{Type: addresses}}.Pack([]common.Address{n.bot})
+}
+
+// newGuardingManager 创建启用了应急响应的管理器（模拟模式），规则可以关联通知动作 page
+func newGuardingManager(t *testing.T, cfg *config.Config, rules ...config.AlertRuleConfig) *emergency.Manager {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &argusNode{bot: crypto.PubkeyToAddress(key.PublicKey)}))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	cfg.Emergency = config.EmergencyConfig{
+		Enabled:      true,
+		DryRun:       true,
+		ChainID:      -1,
+		PrivateKey:   hexutil.Encode(crypto.FromECDSA(key)),
+		SafeAddress:  common.HexToAddress("0x5afe").Hex(),
+		ArgusAddress: common.HexToAddress("0xa7905").Hex(),
+		Actions:      []config.ActionConfig{{Name: "page", Type: emergency.ActionNotify}},
+		Rules:        rules,
+		Quorum:       cfg.Emergency.Quorum,
+	}
+	logger := zap.NewNop()
+	manager, err := emergency.NewManager(context.Background(), &cfg.Emergency, httpServer.URL, metrics.NewMetrics(&cfg.Prometheus, logger), logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { manager.Close() })
+	return manager
+}
+
+// TestRunChecks_Timeout 测试卡住的合约不会阻塞其他合约的检查
+func TestRunChecks_Timeout(t *testing.T) {
+	cfg := &config.Config{Monitor: config.MonitorConfig{PollInterval: 30, CheckTimeout: 1, Concurrency: 2}}
+	m := newTestMonitor(t, cfg)
+
+	hanging := newFakeAccount("hanging", true)
+	fast := []*fakeAccount{newFakeAccount("a", false), newFakeAccount("b", false), newFakeAccount("c", false)}
+	m.ethAccounts = []contracts.Account{hanging, fast[0]}
+	m.inkAccounts = []contracts.Account{fast[1], fast[2]}
+
+	var tasks []checkTask
+	var done atomic.Int32
+	for _, j := range m.newJobs(time.Now()) {
+		task := j.task
+		task.done = func() { done.Add(1) }
+		tasks = append(tasks, task)
+	}
+
+	start := time.Now()
+	m.runChecks(context.Background(), &snapshot{}, tasks)
+	elapsed := time.Since(start)
+
+	assert.Less(t, elapsed, 3*time.Second)
+	assert.EqualValues(t, 1, hanging.calls.Load())
+	for _, account := range fast {
+		assert.EqualValues(t, 1, account.calls.Load(), account.Name())
+	}
+	assert.EqualValues(t, 4, done.Load())
+}
+
+// TestJobReschedule 测试检查的调度时间计算
+func TestJobReschedule(t *testing.T) {
+	now := time.Now()
+	j := &job{every: checkInterval{interval: 10 * time.Second}, next: now}
+
+	// 按计划时间推进，不累积执行延迟
+	j.reschedule(now.Add(time.Second))
+	assert.Equal(t, now.Add(10*time.Second), j.next)
+
+	// 落后超过一个间隔时从当前时间重新计算
+	late := now.Add(time.Minute)
+	j.reschedule(late)
+	assert.Equal(t, late.Add(10*time.Second), j.next)
+
+	// 随机延迟不超过上限
+	j.every.jitter = 2 * time.Second
+	prev := j.next
+	j.reschedule(prev)
+	assert.GreaterOrEqual(t, j.next.Sub(prev), 10*time.Second)
+	assert.Less(t, j.next.Sub(prev), 12*time.Second)
+}
+
+// TestEventTrigger 测试事件只触发监听该事件的合约检查
+func TestEventTrigger(t *testing.T) {
+	cfg := &config.Config{Monitor: config.MonitorConfig{PollInterval: 30, Events: config.EventsConfig{Enabled: true}}}
+	m := newTestMonitor(t, cfg)
+	m.watches = make(map[string]*eventWatch)
+
+	portal := contracts.NewInkOptimismPortal(common.HexToAddress("0x01"))
+	oracle := contracts.NewChaosPushOracle(common.HexToAddress("0x02"))
+	m.ethAccounts = []contracts.Account{portal}
+	m.inkAccounts = []contracts.Account{oracle}
+	require.NoError(t, m.addEventWatch("ethereum", portal, nil))
+	aggregator := common.HexToAddress("0x03")
+	require.NoError(t, m.addEventWatch("ink", oracle, &config.ContractConfig{EventAddress: aggregator.Hex(), Events: []string{"AnswerUpdated(int256, uint256, uint256)"}}))
+	assert.Equal(t, []common.Address{aggregator}, m.eventQuery("ink").Addresses, "价格源监听 aggregator 发出的事件")
+
+	// 价格源未配置 event_address 时不监听默认事件，显式配置 events 时报错
+	feed := contracts.NewRoundFeed("eth_usd", common.HexToAddress("0x04"), 8, 0)
+	require.NoError(t, m.addEventWatch("ink", feed, nil))
+	require.NoError(t, m.addEventWatch("ink", feed, &config.ContractConfig{}))
+	assert.NotContains(t, m.watches, metricKey("ink", feed.Name()))
+	assert.ErrorContains(t, m.addEventWatch("ink", feed, &config.ContractConfig{Events: []string{"AnswerUpdated(int256,uint256,uint256)"}}), "event_address")
+
+	q := m.eventQuery("ethereum")
+	assert.Equal(t, []common.Address{portal.Address()}, q.Addresses)
+	assert.Len(t, q.Topics[0], len(pauseEvents))
+
+	jobs := m.newJobs(time.Now())
+	paused := types.Log{
+		Address:     portal.Address(),
+		Topics:      []common.Hash{crypto.Keccak256Hash([]byte("Paused(address)"))},
+		BlockNumber: 100,
+	}
+	due, atLeast := m.triggered(jobs, trigger{chain: "ethereum", log: &paused})
+	require.Len(t, due, 1)
+	assert.Equal(t, portal.Name(), due[0].task.contract.Name())
+	assert.Equal(t, uint64(100), atLeast["ethereum"])
+
+	// 正在执行的检查不会重复触发
+	due, _ = m.triggered(jobs, trigger{chain: "ethereum", log: &paused})
+	assert.Empty(t, due)
+
+	// 其他事件不触发
+	other := types.Log{Address: oracle.Address(), Topics: []common.Hash{crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))}}
+	due, _ = m.triggered(jobs, trigger{chain: "ink", log: &other})
+	assert.Empty(t, due)
+
+	_, err := newEventWatch(common.Address{}, []string{"Paused(address"})
+	assert.Error(t, err)
+}
+
+// TestHeadDue 测试每 N 个新区块检查一次对应链的合约
+func TestHeadDue(t *testing.T) {
+	cfg := &config.Config{Monitor: config.MonitorConfig{PollInterval: 30}, Ink: config.ChainConfig{EveryBlocks: 3}}
+	m := newTestMonitor(t, cfg)
+	m.ethAccounts = []contracts.Account{newFakeAccount("l1", false)}
+	m.inkAccounts = []contracts.Account{newFakeAccount("l2", false)}
+
+	now := time.Now()
+	jobs := m.newJobs(now)
+	lastHead := make(map[string]uint64)
+
+	// 第一个新区块立即检查，并推迟时间调度
+	due := m.headDue(jobs, head{chain: "ink", number: 100}, lastHead, now)
+	require.Len(t, due, 1)
+	assert.Equal(t, "l2", due[0].task.contract.Name())
+	assert.Equal(t, now.Add(30*time.Second), due[0].next)
+	due[0].running.Store(false)
+
+	// 不足 N 个区块不检查
+	assert.Empty(t, m.headDue(jobs, head{chain: "ink", number: 102}, lastHead, now))
+	assert.Len(t, m.headDue(jobs, head{chain: "ink", number: 103}, lastHead, now), 1)
+
+	// 未配置 every_blocks 的链不按新区块检查
+	assert.Empty(t, m.headDue(jobs, head{chain: "ethereum", number: 100}, lastHead, now))
+}
+
+// TestReady 测试各链最近一次检查成功超过允许的间隔数时未就绪
+func TestReady(t *testing.T) {
+	cfg := &config.Config{Monitor: config.MonitorConfig{PollInterval: 10}, Server: config.ServerConfig{ReadyIntervals: 2}}
+	m := newTestMonitor(t, cfg)
+	l1, l2, slow := newFakeAccount("l1", false), newFakeAccount("l2", false), newFakeAccount("slow", false)
+	m.ethAccounts = []contracts.Account{l1}
+	m.inkAccounts = []contracts.Account{l2, slow}
+	m.intervals[metricKey("ink", "slow")] = checkInterval{interval: time.Minute, jitter: 5 * time.Second}
+
+	// 尚未检查成功
+	assert.ErrorContains(t, m.Ready(), "ethereum 合约 l1 尚未检查成功")
+
+	m.status.success("ethereum", l1, 1, 100)
+	m.status.failure("ink", l2, assert.AnError)
+	assert.ErrorContains(t, m.Ready(), "ink 合约 l2")
+
+	// 同一条链上其他合约检查成功不能代替未成功的合约
+	m.status.success("ink", slow, 3, 200)
+	assert.ErrorContains(t, m.Ready(), "ink 合约 l2 尚未检查成功")
+
+	m.status.success("ink", l2, 2, 200)
+	require.NoError(t, m.Ready())
+	assert.ErrorContains(t, m.ready(time.Now().Add(21*time.Second)), "超过 20s")
+
+	// 每个合约按各自的检查间隔判断
+	m.ethAccounts = nil
+	m.inkAccounts = []contracts.Account{slow}
+	require.NoError(t, m.ready(time.Now().Add(2*time.Minute)))
+	assert.ErrorContains(t, m.ready(time.Now().Add(131*time.Second)), "ink 合约 slow 已 2m11s 没有检查成功，超过 2m10s")
+	m.inkAccounts = []contracts.Account{l2, slow}
+
+	status := m.Status()
+	assert.True(t, status.Ready)
+	require.Len(t, status.Accounts, 2)
+	assert.Equal(t, "20s", status.Accounts[0].MaxAge)
+	assert.Equal(t, "2m10s", status.Accounts[1].MaxAge)
+	assert.Equal(t, uint64(200), status.Accounts[0].Block)
+	assert.Empty(t, status.Accounts[0].LastError, "检查成功后清空错误")
+}
+
+// scrape 返回 /metrics 的内容
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+// feedNode 模拟价格源所在的节点，eth_call 按 latestRoundData() 的格式返回 answer，down 中的地址调用失败
+type feedNode struct {
+	answer int64
+
+	mu   sync.Mutex
+	down map[common.Address]bool
+}
+
+func (n *feedNode) Call(args map[string]interface{}, block interface{}) (hexutil.Bytes, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if to, _ := args["to"].(string); n.down[common.HexToAddress(to)] {
+		return nil, errors.New("execution reverted")
+	}
+	var result []byte
+	for _, word := range []int64{1, n.answer, time.Now().Unix(), time.Now().Unix(), 1} {
+		result = append(result, common.BigToHash(big.NewInt(word)).Bytes()...)
+	}
+	return result, nil
+}
+
+func (n *feedNode) setDown(address string, down bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.down[common.HexToAddress(address)] = down
+}
+
+// TestOnFailure 测试按 contracts[].on_failure 处理检查失败的合约指标、附加指标和价格偏差指标
+func TestOnFailure(t *testing.T) {
+	node := &feedNode{answer: 3000e8, down: make(map[common.Address]bool)}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	addresses := map[string]string{
+		"kept":    common.HexToAddress("0x01").Hex(),
+		"nan":     common.HexToAddress("0x02").Hex(),
+		"deleted": common.HexToAddress("0x03").Hex(),
+	}
+	cfg := &config.Config{
+		InkRPC:  httpServer.URL,
+		Monitor: config.MonitorConfig{PollInterval: 30, OnFailure: config.OnFailureNaN},
+		Ink: config.ChainConfig{Contracts: []config.ContractConfig{
+			{Name: "kept", Type: contracts.TypePriceFeed, Address: addresses["kept"], Method: "latestRoundData", Decimals: 8, OnFailure: config.OnFailureKeep},
+			{Name: "nan", Type: contracts.TypePriceFeed, Address: addresses["nan"], Method: "latestRoundData", Decimals: 8},
+			{Name: "deleted", Type: contracts.TypePriceFeed, Address: addresses["deleted"], Method: "latestRoundData", Decimals: 8, OnFailure: config.OnFailureDelete},
+		}},
+	}
+	reference := []config.ReferenceFeedConfig{{Chain: "ink", Address: common.HexToAddress("0x10").Hex(), Decimals: 8}}
+	for _, name := range []string{"kept", "nan", "deleted"} {
+		cfg.PriceDeviations = append(cfg.PriceDeviations, config.PriceDeviationConfig{Asset: strings.ToUpper(name), Feed: name, References: reference})
+	}
+
+	logger := zap.NewNop()
+	metricsManager := metrics.NewMetrics(&cfg.Prometheus, logger)
+	emergencyManager, err := emergency.NewManager(context.Background(), &cfg.Emergency, "", metricsManager, logger)
+	require.NoError(t, err)
+	m, err := NewMonitor(cfg, nil, metricsManager, emergencyManager, logger)
+	require.NoError(t, err)
+	m.registerMetrics()
+
+	caller, err := client.NewContractCaller(cfg.InkRPC, logger)
+	require.NoError(t, err)
+	t.Cleanup(caller.Close)
+	snap := &snapshot{callers: map[string]*client.ContractCaller{"ink": caller}}
+	poll := func() {
+		for _, account := range m.inkAccounts {
+			m.pollInkContract(context.Background(), snap, account)
+		}
+	}
+
+	// 每个合约导出的指标：合约指标、附加指标（以 round_id 为例）、价格偏差和参考价格
+	series := func(name string) []string {
+		labels := `{chain="ink",contract="` + name + `"}`
+		deviation := `{asset="` + strings.ToUpper(name) + `",feed="` + name + `"}`
+		return []string{
+			metrics.GetMetricName("ink", name) + labels,
+			metrics.GetDetailMetricName("ink", name, contracts.DetailRoundID) + labels,
+			metrics.GetPriceDeviationMetricName(strings.ToUpper(name)) + deviation,
+			metrics.GetPriceReferenceMetricName(strings.ToUpper(name)) + deviation,
+		}
+	}
+	values := []string{" 3000", " 1", " 0", " 3000"}
+
+	poll()
+	body := scrape(t, m.metrics)
+	for _, name := range []string{"kept", "nan", "deleted"} {
+		for i, line := range series(name) {
+			assert.Contains(t, body, line+values[i]+"\n")
+		}
+	}
+
+	for _, address := range addresses {
+		node.setDown(address, true)
+	}
+	poll()
+	body = scrape(t, m.metrics)
+	for i, line := range series("kept") {
+		assert.Contains(t, body, line+values[i]+"\n", "keep 保留最后一次成功的值")
+	}
+	for _, line := range series("nan") {
+		assert.Contains(t, body, line+" NaN\n")
+	}
+	for _, line := range series("deleted") {
+		assert.NotContains(t, body, line)
+	}
+	for name := range addresses {
+		assert.Contains(t, body, `ink_eth_monitor_contract_up{chain="ink",contract="`+name+`"} 0`)
+	}
+
+	// 检查成功后恢复
+	node.setDown(addresses["deleted"], false)
+	poll()
+	body = scrape(t, m.metrics)
+	for i, line := range series("deleted") {
+		assert.Contains(t, body, line+values[i]+"\n")
+	}
+	assert.Contains(t, body, `ink_eth_monitor_contract_up{chain="ink",contract="deleted"} 1`)
+}
+
+// TestTakeSnapshot_UsedChains 测试只固定和预读本批检查读取的链
+func TestTakeSnapshot_UsedChains(t *testing.T) {
+	cfg := &config.Config{
+		EthRPC:  newChainNode(t, &chainNode{head: 1000}),
+		InkRPC:  newChainNode(t, &chainNode{head: 500}),
+		Monitor: config.MonitorConfig{PollInterval: 30},
+	}
+	m := newTestMonitor(t, cfg)
+	clientManager, err := client.NewClientManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(clientManager.Close)
+	m.clientManager = clientManager
+	m.deviations = make(map[string]*deviationCheck)
+
+	inkFeed := newFixedPrice("ink_feed", 1, nil)
+	ethFeed := newFixedPrice("eth_feed", 1, nil)
+	m.inkAccounts = []contracts.Account{inkFeed}
+	m.ethAccounts = []contracts.Account{ethFeed}
+
+	snap := m.takeSnapshot(context.Background(), []checkTask{{chain: "ink", contract: inkFeed}}, nil)
+	assert.Equal(t, uint64(500), snap.block("ink"))
+	assert.Zero(t, snap.block("ethereum"), "本批不读取的链不固定区块")
+	assert.NotNil(t, snap.caller("ethereum"))
+
+	// price_diff 对比的价格源所在的链同样固定
+	m.alerts[metricKey("ink", "ink_feed")] = &config.AlertConfig{Type: config.AlertTypePriceDiff, CompareWith: "eth_feed", Threshold: 0.05}
+	snap = m.takeSnapshot(context.Background(), []checkTask{{chain: "ink", contract: inkFeed}}, nil)
+	assert.Equal(t, uint64(1000), snap.block("ethereum"))
+	assert.Equal(t, map[string]bool{"ethereum": true, "ink": true}, m.batchChains([]checkTask{{chain: "ink", contract: inkFeed}}))
+	assert.Equal(t, map[string]bool{"ethereum": true}, m.batchChains([]checkTask{{chain: "ethereum", contract: ethFeed}}))
+}
+
+// detailedPrice 带附加指标的测试价格源
+type detailedPrice struct {
+	*fixedPrice
+	details map[string]float64
+}
+
+func (p *detailedPrice) Read(ctx context.Context, caller *client.ContractCaller) (*contracts.Reading, error) {
+	return &contracts.Reading{Value: p.price, Details: p.details}, p.err
+}
+
+func (p *detailedPrice) DetailNames() []string {
+	names := make([]string, 0, len(p.details))
+	for name := range p.details {
+		names = append(names, name)
+	}
+	return names
+}
+
+// flakyPrice 前 fails 次读取失败的测试价格源
+type flakyPrice struct {
+	contracts.BaseContract
+	fails int
+}
+
+func (p *flakyPrice) Monitor(ctx context.Context, caller *client.ContractCaller) (float64, error) {
+	if p.fails > 0 {
+		p.fails--
+		return 0, errors.New("down")
+	}
+	return 1, nil
+}
+
+// TestCheckDetails_Retry 测试附加指标在检查成功后只评估一次告警规则，重试不会重复计入
+func TestCheckDetails_Retry(t *testing.T) {
+	cfg := &config.Config{
+		Monitor: config.MonitorConfig{PollInterval: 30, RetryTimes: 3},
+		Emergency: config.EmergencyConfig{Rules: []config.AlertRuleConfig{
+			{Name: "feed_stale", Metric: "ink_eth_monitor_ink_feed_*", Operator: ">", Threshold: 3600},
+		}},
+	}
+	m := newTestMonitor(t, cfg)
+	core, logs := observer.New(zap.WarnLevel)
+	m.logger = zap.New(core)
+	m.emergency, _ = emergency.NewManager(context.Background(), &cfg.Emergency, "", m.metrics, m.logger)
+
+	feed := &detailedPrice{fixedPrice: newFixedPrice("feed", 1, nil), details: map[string]float64{"age_seconds": 7200}}
+	compare := &flakyPrice{BaseContract: contracts.NewBaseContract("compare", common.Address{}, contracts.TypePriceFeed), fails: 2}
+	m.inkAccounts = []contracts.Account{feed, compare}
+	m.alerts[metricKey("ink", "feed")] = &config.AlertConfig{Type: config.AlertTypePriceDiff, CompareWith: "compare", Threshold: 0.05}
+
+	// 前两次尝试在读取附加指标之后失败，第三次成功
+	m.pollInkContract(context.Background(), &snapshot{}, feed)
+	hits := logs.FilterMessage("告警规则命中").All()
+	require.Len(t, hits, 1)
+	assert.Equal(t, metrics.GetDetailMetricName("ink", "feed", "age_seconds"), hits[0].ContextMap()["metric"])
+	assert.Contains(t, scrape(t, m.metrics), "age_seconds")
+}
+
+// TestValidateMetricNames 测试合约名称与其他指标重名时启动失败
+func TestValidateMetricNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		ink     []contracts.Account
+		wantErr string
+	}{
+		{
+			name: "名称不冲突",
+			ink:  []contracts.Account{contracts.NewRoundFeed("feed", common.Address{}, 8, 0), newFixedPrice("other", 1, nil)},
+		},
+		{
+			name:    "与链存活指标重名",
+			ink:     []contracts.Account{newFixedPrice("head_age_seconds", 1, nil)},
+			wantErr: "ink 链存活检查 head_age_seconds 与 ink 合约 head_age_seconds 都导出 ink_eth_monitor_ink_head_age_seconds",
+		},
+		{
+			name:    "与附加指标重名",
+			ink:     []contracts.Account{contracts.NewRoundFeed("feed", common.Address{}, 8, 0), newFixedPrice("feed_round_stale", 1, nil)},
+			wantErr: "ink 合约 feed 的附加指标 round_stale 与 ink 合约 feed_round_stale",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMonitor(t, &config.Config{Monitor: config.MonitorConfig{PollInterval: 30}})
+			m.inkAccounts = tt.ink
+			err := m.validateMetricNames()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	// 两个资产名称转换为指标名称后相同
+	m := newTestMonitor(t, &config.Config{Monitor: config.MonitorConfig{PollInterval: 30}})
+	m.deviations = map[string]*deviationCheck{
+		metricKey("ink", "a"): {asset: "wst-ETH"},
+		metricKey("ink", "b"): {asset: "wst_eth"},
+	}
+	assert.ErrorContains(t, m.validateMetricNames(), "ink_eth_monitor_price_")
+}
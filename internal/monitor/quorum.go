@@ -0,0 +1,179 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+)
+
+// quorumAnswer 单个节点的读取结果
+type quorumAnswer struct {
+	endpoint string
+	value    float64
+	details  map[string]float64 // 附加指标，提供附加指标的合约才有
+	err      error
+}
+
+// quorumRead 在同一区块从多个节点读取合约值，达到法定一致数时返回一致的值
+// 提供附加指标的合约同时比较附加指标，指标值和所有附加指标都一致才视为一致；
+// 使用本轮固定的区块，未固定时使用各节点都已同步的区块；
+// 任一节点的结果与多数不一致时记录指标和每个节点的结果
+func (m *Monitor) quorumRead(ctx context.Context, snap *snapshot, chain string, contract contracts.Account) (*contracts.Reading, error) {
+	q := m.cfg.Emergency.Quorum
+	providers := m.chainClient(chain).Providers(q.Providers)
+	if len(providers) < q.Min {
+		return nil, fmt.Errorf("可用节点数 %d 少于法定一致数 %d", len(providers), q.Min)
+	}
+
+	var block *big.Int
+	if pinned := snap.block(chain); pinned > 0 {
+		block = new(big.Int).SetUint64(pinned)
+	} else {
+		var err error
+		if block, err = commonBlock(ctx, providers, q.Min); err != nil {
+			return nil, err
+		}
+	}
+
+	answers := make([]quorumAnswer, len(providers))
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider *client.ContractCaller) {
+			defer wg.Done()
+			answers[i] = readAnswer(ctx, provider.AtBlock(block), contract)
+			answers[i].endpoint = provider.Endpoint()
+		}(i, provider)
+	}
+	wg.Wait()
+
+	agreed, support, answered := agree(answers, q.Tolerance)
+	reached := support >= q.Min
+	if support < answered || !reached {
+		m.metrics.IncQuorumDisagreement(chain, contract.Name(), reached)
+
+		fields := []zap.Field{
+			zap.String("chain", chain),
+			zap.String("contract", contract.Name()),
+			zap.String("block", block.String()),
+			zap.Int("support", support),
+			zap.Int("quorum", q.Min),
+		}
+		for _, answer := range answers {
+			if answer.err != nil {
+				fields = append(fields, zap.String(answer.endpoint, "error: "+answer.err.Error()))
+			} else {
+				fields = append(fields, zap.Float64(answer.endpoint, answer.value))
+			}
+		}
+		if reached {
+			m.logger.Warn("RPC节点读取结果不一致，多数节点达成一致", fields...)
+		} else {
+			m.logger.Error("RPC节点读取结果未达到法定一致数", fields...)
+		}
+	}
+
+	if !reached {
+		return nil, fmt.Errorf("%s 在区块 %s 未达到法定一致数: %d/%d", contract.Name(), block, support, q.Min)
+	}
+	return &contracts.Reading{Value: agreed.value, Details: agreed.details}, nil
+}
+
+// readAnswer 从单个节点读取合约值，提供附加指标的合约同时读取附加指标
+func readAnswer(ctx context.Context, caller *client.ContractCaller, contract contracts.Account) quorumAnswer {
+	detailed, ok := contract.(contracts.DetailedAccount)
+	if !ok {
+		value, err := contract.Monitor(ctx, caller)
+		return quorumAnswer{value: value, err: err}
+	}
+	reading, err := detailed.Read(ctx, caller)
+	if err != nil {
+		return quorumAnswer{err: err}
+	}
+	return quorumAnswer{value: reading.Value, details: reading.Details}
+}
+
+// commonBlock 返回所有节点都已同步的区块（各节点最新高度的最小值）
+func commonBlock(ctx context.Context, providers []*client.ContractCaller, minProviders int) (*big.Int, error) {
+	heads := make([]uint64, len(providers))
+	errs := make([]error, len(providers))
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider *client.ContractCaller) {
+			defer wg.Done()
+			heads[i], errs[i] = provider.BlockNumber(ctx)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	var block uint64
+	responded := 0
+	for i := range providers {
+		if errs[i] != nil {
+			continue
+		}
+		if responded == 0 || heads[i] < block {
+			block = heads[i]
+		}
+		responded++
+	}
+	if responded < minProviders {
+		return nil, fmt.Errorf("只有 %d 个节点返回区块高度，少于法定一致数 %d", responded, minProviders)
+	}
+	return new(big.Int).SetUint64(block), nil
+}
+
+// agree 找出支持者最多的结果，返回该结果、支持的节点数和成功返回的节点数
+// 两个结果的指标值和每个附加指标的相对误差都不超过 tolerance 时视为一致
+func agree(answers []quorumAnswer, tolerance float64) (agreed quorumAnswer, support, answered int) {
+	for i, a := range answers {
+		if a.err != nil {
+			continue
+		}
+		answered++
+		count := 0
+		for j, b := range answers {
+			if b.err == nil && (i == j || sameAnswer(a, b, tolerance)) {
+				count++
+			}
+		}
+		if count > support {
+			agreed, support = a, count
+		}
+	}
+	return agreed, support, answered
+}
+
+// sameAnswer 判断两个节点的读取结果是否一致
+// 由本地时钟计算的附加指标（如 updated_age_seconds）不比较，其对应的链上数据由轮次ID等指标覆盖
+func sameAnswer(a, b quorumAnswer, tolerance float64) bool {
+	if !withinTolerance(a.value, b.value, tolerance) || len(a.details) != len(b.details) {
+		return false
+	}
+	for name, value := range a.details {
+		other, ok := b.details[name]
+		if !ok {
+			return false
+		}
+		if !contracts.IsClockDetail(name) && !withinTolerance(value, other, tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// withinTolerance 判断两个值是否在相对误差范围内一致
+func withinTolerance(a, b, tolerance float64) bool {
+	if a == b {
+		return true
+	}
+	return math.Abs(a-b) <= tolerance*math.Max(math.Abs(a), math.Abs(b))
+}
@@ -0,0 +1,152 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+)
+
+// TestAgree 测试多节点读取结果的一致性判定
+func TestAgree(t *testing.T) {
+	down := errors.New("node down")
+
+	// 全部一致
+	agreed, support, answered := agree([]quorumAnswer{{value: 1}, {value: 1}, {value: 1}}, 0)
+	assert.Equal(t, 1.0, agreed.value)
+	assert.Equal(t, 3, support)
+	assert.Equal(t, 3, answered)
+
+	// 一个节点返回不同的值
+	agreed, support, answered = agree([]quorumAnswer{{value: 1}, {value: 0}, {value: 1}}, 0)
+	assert.Equal(t, 1.0, agreed.value)
+	assert.Equal(t, 2, support)
+	assert.Equal(t, 3, answered)
+
+	// 失败的节点不计入
+	_, support, answered = agree([]quorumAnswer{{value: 0}, {err: down}, {value: 1}}, 0)
+	assert.Equal(t, 1, support)
+	assert.Equal(t, 2, answered)
+
+	// 相对误差内视为一致
+	agreed, support, _ = agree([]quorumAnswer{{value: 3000}, {value: 3001}, {value: 3500}}, 0.001)
+	assert.Equal(t, 3000.0, agreed.value)
+	assert.Equal(t, 2, support)
+
+	_, support, answered = agree([]quorumAnswer{{err: down}, {err: down}}, 0)
+	assert.Equal(t, 0, support)
+	assert.Equal(t, 0, answered)
+
+	// 指标值一致但附加指标不一致
+	stale := map[string]float64{"age_seconds": 7200}
+	fresh := map[string]float64{"age_seconds": 60}
+	agreed, support, _ = agree([]quorumAnswer{{value: 1, details: stale}, {value: 1, details: fresh}, {value: 1, details: fresh}}, 0)
+	assert.Equal(t, 2, support)
+	assert.Equal(t, fresh, agreed.details)
+
+	// 由本地时钟计算的附加指标不比较
+	now := map[string]float64{contracts.DetailRoundID: 7, contracts.DetailUpdatedAge: 60.001}
+	later := map[string]float64{contracts.DetailRoundID: 7, contracts.DetailUpdatedAge: 60.002}
+	_, support, _ = agree([]quorumAnswer{{value: 1, details: now}, {value: 1, details: later}}, 0)
+	assert.Equal(t, 2, support)
+}
+
+// newQuorumMonitor 创建 INK 链连接多个测试节点的监控器
+func newQuorumMonitor(t *testing.T, quorum config.QuorumConfig, nodes ...*chainNode) *Monitor {
+	cfg := &config.Config{
+		EthRPC:    newChainNode(t, &chainNode{head: 1}),
+		Monitor:   config.MonitorConfig{PollInterval: 30},
+		Emergency: config.EmergencyConfig{Quorum: quorum},
+	}
+	for _, node := range nodes {
+		cfg.Ink.RpcURLs = append(cfg.Ink.RpcURLs, newChainNode(t, node))
+	}
+	cfg.InkRPC = cfg.Ink.RpcURLs[0]
+	clientManager, err := client.NewClientManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(clientManager.Close)
+
+	m := newTestMonitor(t, cfg)
+	m.clientManager = clientManager
+	return m
+}
+
+// TestQuorumRead 测试多节点在同一区块读取并按法定一致数判定
+func TestQuorumRead(t *testing.T) {
+	nodes := []*chainNode{{head: 105, l1Origin: 42}, {head: 98, l1Origin: 42}, {head: 100, l1Origin: 41}}
+	m := newQuorumMonitor(t, config.QuorumConfig{Min: 2}, nodes...)
+	feed := contracts.NewPriceFeed("feed", common.HexToAddress("0x01"), "latestAnswer", 0)
+
+	// 未固定区块时使用各节点都已同步的区块，一个节点不一致时多数节点仍达成一致
+	reading, err := m.quorumRead(context.Background(), &snapshot{}, "ink", feed)
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, reading.Value)
+	for _, node := range nodes {
+		assert.Equal(t, hexutil.EncodeUint64(98), node.calledAt)
+	}
+	assert.Contains(t, scrape(t, m.metrics), `ink_eth_monitor_quorum_disagreements_total{chain="ink",contract="feed",quorum="true"} 1`)
+
+	// 固定了区块时在该区块读取
+	_, err = m.quorumRead(context.Background(), &snapshot{blocks: map[string]uint64{"ink": 103}}, "ink", feed)
+	require.NoError(t, err)
+	for _, node := range nodes {
+		assert.Equal(t, hexutil.EncodeUint64(103), node.calledAt)
+	}
+
+	// 各节点结果都不一致
+	nodes[1].l1Origin = 40
+	_, err = m.quorumRead(context.Background(), &snapshot{}, "ink", feed)
+	assert.ErrorContains(t, err, "未达到法定一致数: 1/2")
+	assert.Contains(t, scrape(t, m.metrics), `ink_eth_monitor_quorum_disagreements_total{chain="ink",contract="feed",quorum="false"} 1`)
+
+	// 参与的节点数少于法定一致数
+	m = newQuorumMonitor(t, config.QuorumConfig{Min: 2}, &chainNode{head: 100, l1Origin: 42})
+	_, err = m.quorumRead(context.Background(), &snapshot{}, "ink", feed)
+	assert.ErrorContains(t, err, "可用节点数 1 少于法定一致数 2")
+	m = newQuorumMonitor(t, config.QuorumConfig{Min: 3, Providers: 2}, nodes...)
+	_, err = m.quorumRead(context.Background(), &snapshot{}, "ink", feed)
+	assert.ErrorContains(t, err, "可用节点数 2 少于法定一致数 3")
+}
+
+// TestValidateQuorum 测试关联了应急动作的指标所在的链至少配置法定一致数个节点
+func TestValidateQuorum(t *testing.T) {
+	nodes := []*chainNode{{head: 100}, {head: 100}}
+	feed := &detailedPrice{fixedPrice: newFixedPrice("feed", 1, nil), details: map[string]float64{contracts.DetailRoundStale: 0}}
+
+	tests := []struct {
+		name    string
+		quorum  config.QuorumConfig
+		metric  string
+		wantErr string
+	}{
+		{name: "未启用交叉验证", quorum: config.QuorumConfig{}, metric: "ink_eth_monitor_ink_feed"},
+		{name: "指标值", quorum: config.QuorumConfig{Min: 2}, metric: "ink_eth_monitor_ink_feed"},
+		{name: "指标值节点数不足", quorum: config.QuorumConfig{Min: 3}, metric: "ink_eth_monitor_ink_feed", wantErr: "ink 的指标关联了应急动作，但只配置了 2 个RPC节点"},
+		{name: "附加指标节点数不足", quorum: config.QuorumConfig{Min: 3}, metric: "ink_eth_monitor_ink_feed_round_stale", wantErr: "ink 的指标关联了应急动作"},
+		{name: "未关联应急动作的链", quorum: config.QuorumConfig{Min: 3}, metric: "ink_eth_monitor_ethereum_*"},
+		{name: "providers 小于 min", quorum: config.QuorumConfig{Min: 2, Providers: 1}, metric: "ink_eth_monitor_ink_feed", wantErr: "providers (1) 不能小于 min (2)"},
+		{name: "tolerance 为负数", quorum: config.QuorumConfig{Min: 2, Tolerance: -0.01}, metric: "ink_eth_monitor_ink_feed", wantErr: "tolerance 不能为负数"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newQuorumMonitor(t, tt.quorum, nodes...)
+			m.emergency = newGuardingManager(t, m.cfg, config.AlertRuleConfig{Name: "guard", Metric: tt.metric, Operator: ">", Threshold: 0, Actions: []string{"page"}})
+			m.inkAccounts = []contracts.Account{feed}
+			err := m.validateQuorum()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package monitor
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// checkInterval 检查的调度间隔
+type checkInterval struct {
+	interval time.Duration
+	jitter   time.Duration // 每次调度增加的随机延迟上限
+}
+
+// job 调度中的合约检查
+type job struct {
+	task    checkTask
+	every   checkInterval
+	watch   *eventWatch // 触发立即检查的事件，nil 表示只按间隔调度
+	next    time.Time
+	running atomic.Bool // 上一次检查尚未完成时不会再次执行
+}
+
+// reschedule 计算下一次执行时间，执行落后超过一个间隔时从当前时间重新计算
+func (j *job) reschedule(now time.Time) {
+	j.next = j.next.Add(j.every.interval)
+	if j.next.Before(now) {
+		j.next = now.Add(j.every.interval)
+	}
+	if j.every.jitter > 0 {
+		j.next = j.next.Add(rand.N(j.every.jitter))
+	}
+}
+
+// newJobs 为所有合约创建调度任务，启动时全部立即执行一次
+func (m *Monitor) newJobs(now time.Time) []*job {
+	var jobs []*job
+	for _, chain := range []string{"ethereum", "ink"} {
+		for _, contract := range m.chainAccounts(chain) {
+			jobs = append(jobs, &job{
+				task:  checkTask{chain: chain, contract: contract},
+				every: m.intervalOf(chain, contract.Name()),
+				watch: m.watches[metricKey(chain, contract.Name())],
+				next:  now,
+			})
+		}
+	}
+	return jobs
+}
+
+// intervalOf 返回合约的检查间隔，未单独配置时使用 monitor.poll_interval 和 monitor.jitter
+func (m *Monitor) intervalOf(chain, contractName string) checkInterval {
+	if every, ok := m.intervals[metricKey(chain, contractName)]; ok {
+		return every
+	}
+	return checkInterval{
+		interval: m.cfg.Monitor.GetPollDuration(),
+		jitter:   m.cfg.Monitor.GetJitter(),
+	}
+}
+
+// schedule 按每个合约各自的间隔调度检查，直到 ctx 取消或调用 Stop
+// 同一时刻到期的检查作为一批执行（共享区块快照和批量读取），执行完成后推送指标；
+// 监听到事件时立即检查对应的合约；配置了 every_blocks 的链每 N 个新区块检查一次，
+// 时间间隔调度作为新区块通知中断时的兜底；RPC节点健康检查按 monitor.poll_interval 执行
+func (m *Monitor) schedule(ctx context.Context) error {
+	now := time.Now()
+	jobs := m.newJobs(now)
+	healthEvery := m.cfg.Monitor.GetPollDuration()
+	nextHealth := now.Add(healthEvery)
+
+	var (
+		wg            sync.WaitGroup
+		healthRunning atomic.Bool
+	)
+	defer wg.Wait()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	lastHead := make(map[string]uint64) // 各链上一次按新区块检查的区块
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("监控服务收到停止信号")
+			return ctx.Err()
+		case <-m.stopChan:
+			m.logger.Info("监控服务停止")
+			return nil
+		case t := <-m.triggers:
+			if due, atLeast := m.triggered(jobs, t); len(due) > 0 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					m.runJobs(ctx, due, atLeast)
+				}()
+			}
+			continue
+		case h := <-m.heads:
+			if due := m.headDue(jobs, h, lastHead, time.Now()); len(due) > 0 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					m.runJobs(ctx, due, map[string]uint64{h.chain: h.number})
+				}()
+			}
+			continue
+		case <-timer.C:
+		}
+
+		now = time.Now()
+		if !now.Before(nextHealth) {
+			nextHealth = now.Add(healthEvery)
+			if healthRunning.CompareAndSwap(false, true) {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer healthRunning.Store(false)
+					m.checkEndpoints(ctx)
+				}()
+			}
+		}
+
+		var due []*job
+		for _, j := range jobs {
+			if now.Before(j.next) {
+				continue
+			}
+			j.reschedule(now)
+			if !j.running.CompareAndSwap(false, true) {
+				m.logger.Warn("上一次检查尚未完成，跳过本次调度",
+					zap.String("chain", j.task.chain),
+					zap.String("contract", j.task.contract.Name()),
+				)
+				continue
+			}
+			due = append(due, j)
+		}
+
+		if len(due) > 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.runJobs(ctx, due, nil)
+			}()
+		}
+
+		timer.Reset(time.Until(nextWake(jobs, nextHealth)))
+	}
+}
+
+// triggered 返回事件触发的检查，正在执行的检查不会重复执行
+// 读取的区块不早于事件所在区块，避免落后的节点读到事件之前的状态
+func (m *Monitor) triggered(jobs []*job, t trigger) ([]*job, map[string]uint64) {
+	var due []*job
+	for _, j := range jobs {
+		if j.task.chain != t.chain || j.watch == nil || (t.log != nil && !j.watch.matches(*t.log)) {
+			continue
+		}
+		if !j.running.CompareAndSwap(false, true) {
+			m.logger.Debug("检查正在执行，忽略事件触发", zap.String("contract", j.task.contract.Name()))
+			continue
+		}
+		due = append(due, j)
+	}
+
+	var atLeast map[string]uint64
+	if t.log != nil {
+		atLeast = map[string]uint64{t.chain: t.log.BlockNumber}
+	}
+	return due, atLeast
+}
+
+// headDue 返回新区块驱动的检查，距上一次检查不足 every_blocks 个区块时返回空
+// 按新区块执行的检查会推迟其时间调度，时间间隔只在新区块通知中断时生效
+func (m *Monitor) headDue(jobs []*job, h head, lastHead map[string]uint64, now time.Time) []*job {
+	every := uint64(m.chainConfig(h.chain).EveryBlocks)
+	if last, ok := lastHead[h.chain]; every == 0 || (ok && h.number < last+every) {
+		return nil
+	}
+	lastHead[h.chain] = h.number
+
+	var due []*job
+	for _, j := range jobs {
+		if j.task.chain != h.chain {
+			continue
+		}
+		j.next = now.Add(j.every.interval)
+		if !j.running.CompareAndSwap(false, true) {
+			m.logger.Debug("检查正在执行，忽略新区块", zap.String("contract", j.task.contract.Name()), zap.Uint64("block", h.number))
+			continue
+		}
+		due = append(due, j)
+	}
+	return due
+}
+
+// runJobs 执行一批到期的检查并推送指标，atLeast 为各链读取区块的下限（可以为 nil）
+func (m *Monitor) runJobs(ctx context.Context, jobs []*job, atLeast map[string]uint64) {
+	tasks := make([]checkTask, len(jobs))
+	for i, j := range jobs {
+		tasks[i] = j.task
+		tasks[i].done = func() { j.running.Store(false) }
+	}
+
+	m.logger.Debug("开始执行到期的合约检查", zap.Int("checks", len(tasks)))
+	snap := m.takeSnapshot(ctx, tasks, atLeast)
+	m.runChecks(ctx, snap, tasks)
+
+	// 推送指标到Prometheus Gateway
+	if err := m.metrics.Push(); err != nil {
+		m.logger.Error("推送指标失败", zap.Error(err))
+	}
+}
+
+// nextWake 返回最早的下一次执行时间
+func nextWake(jobs []*job, nextHealth time.Time) time.Time {
+	next := nextHealth
+	for _, j := range jobs {
+		if j.next.Before(next) {
+			next = j.next
+		}
+	}
+	return next
+}
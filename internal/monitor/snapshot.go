@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/config"
+)
+
+// snapshot 一批检查中各链固定读取的区块，同一批内的相关读取（如剩余容量的两次调用、跨链价格比较）来自同一区块
+type snapshot struct {
+	callers map[string]*client.ContractCaller
+	blocks  map[string]uint64
+}
+
+// takeSnapshot 固定本批检查读取的各链当前的最新区块，并批量读取本批检查的只读调用
+// 首选节点的最新区块低于 atLeast 时使用 atLeast；获取区块高度失败的链本批退回读取最新区块；
+// 本批不读取的链不固定区块也不预读
+func (m *Monitor) takeSnapshot(ctx context.Context, tasks []checkTask, atLeast map[string]uint64) *snapshot {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Monitor.GetCheckTimeout())
+	defer cancel()
+
+	s := &snapshot{
+		callers: make(map[string]*client.ContractCaller),
+		blocks:  make(map[string]uint64),
+	}
+	used := m.batchChains(tasks)
+	for _, chain := range []string{"ethereum", "ink"} {
+		caller := m.chainClient(chain)
+		if !used[chain] {
+			s.callers[chain] = caller
+			continue
+		}
+		pinned, block, err := caller.Pinned(ctx)
+		if err != nil {
+			m.logger.Warn("固定区块失败，本批读取最新区块", zap.String("chain", chain), zap.Error(err))
+			pinned = caller
+		} else if block < atLeast[chain] {
+			block = atLeast[chain]
+			pinned = caller.AtBlock(new(big.Int).SetUint64(block))
+		}
+		s.blocks[chain] = block
+
+		// 本批检查在该链的只读调用合并为 Multicall3 批量读取
+		s.callers[chain] = pinned.Prefetch(ctx, func(c *client.ContractCaller) {
+			m.prefetch(ctx, chain, c, tasks)
+		})
+	}
+	return s
+}
+
+// batchChains 返回本批检查需要读取的链，与 prefetch 覆盖的读取一致
+func (m *Monitor) batchChains(tasks []checkTask) map[string]bool {
+	used := make(map[string]bool)
+	for _, task := range tasks {
+		used[task.chain] = true
+		alert := m.alerts[metricKey(task.chain, task.contract.Name())]
+		if alert != nil && alert.Type == config.AlertTypePriceDiff {
+			if compareChain, _, ok := m.findAccount(alert.CompareWith); ok {
+				used[compareChain] = true
+			}
+		}
+		if check, ok := m.deviations[metricKey(task.chain, task.contract.Name())]; ok {
+			for _, ref := range check.references {
+				used[ref.chain] = true
+			}
+		}
+	}
+	return used
+}
+
+// prefetch 执行本批检查在链上的所有只读调用，调用结果由批量读取缓存
+// 包括合约自身的读取、price_diff 对比的价格源、supply_diff 对比的供应量和价格偏差检查的参考价格源
+func (m *Monitor) prefetch(ctx context.Context, chain string, caller *client.ContractCaller, tasks []checkTask) {
+	for _, task := range tasks {
+		alert := m.alerts[metricKey(task.chain, task.contract.Name())]
+		if task.chain == chain {
+			_, _ = task.contract.Monitor(ctx, caller)
+			if alert != nil && alert.Type == config.AlertTypeSupplyDiff {
+				_, _ = caller.CallUint256(ctx, common.HexToAddress(alert.CompareAddress).Hex(), supplyMethodID(alert))
+			}
+		}
+		if alert != nil && alert.Type == config.AlertTypePriceDiff {
+			if compareChain, compare, ok := m.findAccount(alert.CompareWith); ok && compareChain == chain {
+				_, _ = compare.Monitor(ctx, caller)
+			}
+		}
+		if check, ok := m.deviations[metricKey(task.chain, task.contract.Name())]; ok {
+			for _, ref := range check.references {
+				if ref.chain == chain {
+					_, _ = ref.account.Monitor(ctx, caller)
+				}
+			}
+		}
+	}
+}
+
+// caller 返回链在本批固定区块读取的调用器
+func (s *snapshot) caller(chain string) *client.ContractCaller {
+	return s.callers[chain]
+}
+
+// block 返回链在本批固定的区块高度，0 表示未固定（读取最新区块）
+func (s *snapshot) block(chain string) uint64 {
+	return s.blocks[chain]
+}
@@ -0,0 +1,158 @@
+package monitor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"cs-projects-ink-eth-monitor/internal/client"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/emergency"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// Status 监控服务的运行状态，由 /status 接口以 JSON 返回
+type Status struct {
+	Ready     bool                               `json:"ready"`
+	Reason    string                             `json:"reason,omitempty"` // 未就绪的原因
+	Chains    map[string]ChainStatus             `json:"chains"`
+	Accounts  []AccountStatus                    `json:"accounts"`
+	Endpoints map[string][]client.EndpointHealth `json:"endpoints"`
+	Emergency EmergencyStatus                    `json:"emergency"`
+}
+
+// ChainStatus 链的检查状态
+type ChainStatus struct {
+	LastSuccess time.Time `json:"last_success"` // 最近一次有合约检查成功的时间
+}
+
+// AccountStatus 合约最近一次检查的结果
+type AccountStatus struct {
+	Chain       string    `json:"chain"`
+	Contract    string    `json:"contract"`
+	Type        string    `json:"type"`
+	Metric      string    `json:"metric"`
+	Value       float64   `json:"value"`
+	Block       uint64    `json:"block,omitempty"`
+	LastSuccess time.Time `json:"last_success"`
+	LastCheck   time.Time `json:"last_check"`
+	LastError   string    `json:"last_error,omitempty"` // 最近一次检查失败的错误，之后检查成功时清空
+	MaxAge      string    `json:"max_age"`              // 超过该时间没有检查成功视为未就绪
+}
+
+// EmergencyStatus 应急响应状态
+type EmergencyStatus struct {
+	Enabled   bool                     `json:"enabled"`
+	Triggered bool                     `json:"triggered"`
+	Triggers  []emergency.TriggerState `json:"triggers"`
+}
+
+// statusTracker 记录各合约最近一次检查的结果，零值可以直接使用
+type statusTracker struct {
+	mu       sync.Mutex
+	accounts map[string]*AccountStatus // 按 chain_name 索引
+	chains   map[string]time.Time      // 各链最近一次有合约检查成功的时间
+}
+
+// account 返回合约的状态记录，调用方需持有锁
+func (t *statusTracker) account(chain string, contract contracts.Account) *AccountStatus {
+	if t.accounts == nil {
+		t.accounts = make(map[string]*AccountStatus)
+		t.chains = make(map[string]time.Time)
+	}
+	key := metricKey(chain, contract.Name())
+	s, ok := t.accounts[key]
+	if !ok {
+		s = &AccountStatus{
+			Chain:    chain,
+			Contract: contract.Name(),
+			Type:     contract.Type(),
+			Metric:   metrics.GetMetricName(chain, contract.Name()),
+		}
+		t.accounts[key] = s
+	}
+	return s
+}
+
+// success 记录一次成功的检查
+func (t *statusTracker) success(chain string, contract contracts.Account, value float64, block uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	s := t.account(chain, contract)
+	s.Value, s.Block, s.LastSuccess, s.LastCheck, s.LastError = value, block, now, now, ""
+	t.chains[chain] = now
+}
+
+// failure 记录一次失败的检查（包括重试）
+func (t *statusTracker) failure(chain string, contract contracts.Account, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.account(chain, contract)
+	s.LastCheck, s.LastError = time.Now(), err.Error()
+}
+
+// Status 返回监控服务的运行状态
+func (m *Monitor) Status() Status {
+	now := time.Now()
+	status := Status{Chains: make(map[string]ChainStatus), Accounts: []AccountStatus{}}
+	if err := m.ready(now); err != nil {
+		status.Reason = err.Error()
+	} else {
+		status.Ready = true
+	}
+
+	m.status.mu.Lock()
+	for _, chain := range []string{"ethereum", "ink"} {
+		accounts := m.chainAccounts(chain)
+		if len(accounts) == 0 {
+			continue
+		}
+		status.Chains[chain] = ChainStatus{LastSuccess: m.status.chains[chain]}
+		for _, account := range accounts {
+			s := *m.status.account(chain, account)
+			s.MaxAge = m.readyMaxAge(chain, account.Name()).String()
+			status.Accounts = append(status.Accounts, s)
+		}
+	}
+	m.status.mu.Unlock()
+
+	if m.clientManager != nil {
+		status.Endpoints = m.clientManager.EndpointHealth()
+	}
+	status.Emergency = EmergencyStatus{
+		Enabled:   m.cfg.Emergency.Enabled,
+		Triggered: m.emergency.IsTriggered(),
+		Triggers:  m.emergency.State(),
+	}
+	return status
+}
+
+// Ready 检查监控服务是否就绪：每个监控合约在最近 server.ready_intervals 个自身的检查间隔内都有检查成功
+func (m *Monitor) Ready() error {
+	return m.ready(time.Now())
+}
+
+// ready 按指定时间检查是否就绪，每个合约按各自的检查间隔判断，任一合约超时即未就绪
+func (m *Monitor) ready(now time.Time) error {
+	m.status.mu.Lock()
+	defer m.status.mu.Unlock()
+	for _, chain := range []string{"ethereum", "ink"} {
+		for _, account := range m.chainAccounts(chain) {
+			s, ok := m.status.accounts[metricKey(chain, account.Name())]
+			if !ok || s.LastSuccess.IsZero() {
+				return fmt.Errorf("%s 合约 %s 尚未检查成功", chain, account.Name())
+			}
+			if age, maxAge := now.Sub(s.LastSuccess), m.readyMaxAge(chain, account.Name()); age > maxAge {
+				return fmt.Errorf("%s 合约 %s 已 %s 没有检查成功，超过 %s", chain, account.Name(), age.Truncate(time.Second), maxAge)
+			}
+		}
+	}
+	return nil
+}
+
+// readyMaxAge 返回合约最近一次检查成功的最长允许间隔：合约的检查间隔（含随机延迟）乘以 server.ready_intervals
+func (m *Monitor) readyMaxAge(chain, contractName string) time.Duration {
+	every := m.intervalOf(chain, contractName)
+	return (every.interval + every.jitter) * time.Duration(m.cfg.Server.GetReadyIntervals())
+}
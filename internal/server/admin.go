@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/emergency"
+)
+
+// 管理接口的限制
+const (
+	minTokenLength  = 16
+	maxAdminRequest = 64 << 10
+)
+
+// EmergencyControl 管理接口操作的应急响应，由 emergency.Manager 实现
+type EmergencyControl interface {
+	IsTriggered() bool
+	State() []emergency.TriggerState
+	Disarmed() map[string]time.Time
+	Rearm(rule string) error
+	Reset() error
+	Disarm(rule string, d time.Duration) (time.Time, error)
+	Arm(rule string) error
+	Preview(ctx context.Context, action string) (*emergency.Preview, error)
+	Trigger(ctx context.Context, action, reason string) (*contracts.TxResult, error)
+}
+
+// adminRequest 管理接口的请求参数
+type adminRequest struct {
+	Rule     string `json:"rule,omitempty"`     // 规则名称，为空表示全部规则
+	Action   string `json:"action,omitempty"`   // 应急动作名称
+	Reason   string `json:"reason,omitempty"`   // 手动触发的原因
+	Duration string `json:"duration,omitempty"` // 解除布防时间，如 2h
+}
+
+// adminHandler 处理已认证的管理请求，返回的错误以 422 响应，同时返回的结果附在错误响应中
+type adminHandler func(ctx context.Context, req *adminRequest) (any, error)
+
+// errUnauthorized 认证失败
+var errUnauthorized = errors.New("未授权")
+
+// LoadAdminToken 从 token_file 或 token_env 读取管理接口的 Bearer Token
+func LoadAdminToken(cfg *config.AdminConfig) (string, error) {
+	var token string
+	switch {
+	case cfg.TokenFile != "":
+		data, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("读取管理接口 Token 文件失败: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	case cfg.TokenEnv != "":
+		value, ok := os.LookupEnv(cfg.TokenEnv)
+		if !ok {
+			return "", fmt.Errorf("环境变量 %s 未设置", cfg.TokenEnv)
+		}
+		token = strings.TrimSpace(value)
+	default:
+		return "", fmt.Errorf("管理接口需要配置 token_file 或 token_env")
+	}
+	if len(token) < minTokenLength {
+		return "", fmt.Errorf("管理接口 Token 长度不能少于 %d 个字符", minTokenLength)
+	}
+	return token, nil
+}
+
+// HandleAdmin 注册应急控制管理接口，所有请求需要 Authorization: Bearer <token>，每次调用写入审计日志
+//   - GET  /admin/emergency: 触发状态和解除布防状态
+//   - POST /admin/emergency/rearm: 重新布防，{"rule": ""} 为空时重新布防全部规则
+//   - POST /admin/emergency/disarm: 临时解除布防，{"rule": "", "duration": "2h"}
+//   - POST /admin/emergency/arm: 恢复解除布防的规则，{"rule": ""}
+//   - POST /admin/emergency/preview: 模拟执行动作，{"action": "withdraw_eth"}
+//   - POST /admin/emergency/trigger: 手动执行预览过的动作，{"action": "withdraw_eth", "reason": "..."}
+func (s *Server) HandleAdmin(ctl EmergencyControl, token string, audit *AuditLog) {
+	s.adminRoute("GET /admin/emergency", token, audit, false, func(ctx context.Context, req *adminRequest) (any, error) {
+		return map[string]any{
+			"triggered": ctl.IsTriggered(),
+			"triggers":  ctl.State(),
+			"disarmed":  ctl.Disarmed(),
+		}, nil
+	})
+
+	s.adminRoute("POST /admin/emergency/rearm", token, audit, false, func(ctx context.Context, req *adminRequest) (any, error) {
+		if req.Rule == "" {
+			return nil, ctl.Reset()
+		}
+		return nil, ctl.Rearm(req.Rule)
+	})
+
+	s.adminRoute("POST /admin/emergency/disarm", token, audit, false, func(ctx context.Context, req *adminRequest) (any, error) {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("duration 格式错误: %w", err)
+		}
+		until, err := ctl.Disarm(req.Rule, d)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"until": until}, nil
+	})
+
+	s.adminRoute("POST /admin/emergency/arm", token, audit, false, func(ctx context.Context, req *adminRequest) (any, error) {
+		return nil, ctl.Arm(req.Rule)
+	})
+
+	s.adminRoute("POST /admin/emergency/preview", token, audit, false, func(ctx context.Context, req *adminRequest) (any, error) {
+		preview, err := ctl.Preview(ctx, req.Action)
+		if err != nil {
+			return nil, err
+		}
+		return preview, nil
+	})
+
+	s.adminRoute("POST /admin/emergency/trigger", token, audit, true, func(ctx context.Context, req *adminRequest) (any, error) {
+		if req.Reason == "" {
+			return nil, fmt.Errorf("手动触发需要填写 reason")
+		}
+		// 客户端断开不应中断已发送的应急交易
+		result, err := ctl.Trigger(context.WithoutCancel(ctx), req.Action, req.Reason)
+		resp := map[string]any{"action": req.Action}
+		if result != nil {
+			resp["status"] = result.Status
+			if len(result.Hashes) > 0 {
+				resp["tx_hash"] = result.Hash.Hex()
+			}
+		}
+		return resp, err
+	})
+}
+
+// adminRoute 注册需要认证和审计的管理接口
+// attempt 为 true 时认证通过后、执行前先写入一条 attempt 审计记录，用于会发送交易的接口
+func (s *Server) adminRoute(pattern, token string, audit *AuditLog, attempt bool, handle adminHandler) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		entry := AuditEntry{Time: time.Now(), Remote: r.RemoteAddr, Method: r.Method, Path: r.URL.Path}
+		var before func()
+		if attempt {
+			before = func() {
+				pending := entry
+				pending.Phase = AuditPhaseAttempt
+				audit.Record(pending)
+			}
+		}
+		status, body, err := serveAdmin(r, token, handle, &entry, before)
+		if err != nil {
+			entry.Error = err.Error()
+			failure := map[string]any{"error": err.Error()}
+			if body != nil {
+				failure["result"] = body
+			}
+			body = failure
+		}
+		entry.Status = status
+		audit.Record(entry)
+
+		if status == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if body != nil {
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	})
+}
+
+// serveAdmin 认证并解析请求后调用 handle，返回状态码、响应内容和错误，请求参数记录到审计记录
+// before 不为空时在调用 handle 前执行
+func serveAdmin(r *http.Request, token string, handle adminHandler, entry *AuditEntry, before func()) (int, any, error) {
+	auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+		return http.StatusUnauthorized, nil, errUnauthorized
+	}
+
+	var req adminRequest
+	data, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxAdminRequest))
+	if err != nil {
+		return http.StatusBadRequest, nil, fmt.Errorf("读取请求失败: %w", err)
+	}
+	if data = bytes.TrimSpace(data); len(data) > 0 {
+		if !json.Valid(data) {
+			entry.Request, _ = json.Marshal(string(data))
+			return http.StatusBadRequest, nil, fmt.Errorf("请求不是有效的 JSON")
+		}
+		entry.Request = data
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return http.StatusBadRequest, nil, fmt.Errorf("解析请求失败: %w", err)
+		}
+	}
+
+	if before != nil {
+		before()
+	}
+	resp, err := handle(r.Context(), &req)
+	if err != nil {
+		return http.StatusUnprocessableEntity, resp, err
+	}
+	if resp == nil {
+		resp = map[string]string{"result": "ok"}
+	}
+	return http.StatusOK, resp, nil
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/contracts"
+	"cs-projects-ink-eth-monitor/internal/emergency"
+)
+
+const testToken = "0123456789abcdef"
+
+// fakeControl 记录调用的应急控制，手动触发时记录审计日志中已有的 attempt 记录
+type fakeControl struct {
+	calls     []string
+	auditFile string
+	attempts  int
+}
+
+func (f *fakeControl) IsTriggered() bool               { return false }
+func (f *fakeControl) State() []emergency.TriggerState { return nil }
+func (f *fakeControl) Disarmed() map[string]time.Time  { return nil }
+
+func (f *fakeControl) Rearm(rule string) error {
+	f.calls = append(f.calls, "rearm "+rule)
+	return nil
+}
+
+func (f *fakeControl) Reset() error {
+	f.calls = append(f.calls, "reset")
+	return nil
+}
+
+func (f *fakeControl) Disarm(rule string, d time.Duration) (time.Time, error) {
+	f.calls = append(f.calls, "disarm "+rule+" "+d.String())
+	return time.Now().Add(d), nil
+}
+
+func (f *fakeControl) Arm(rule string) error {
+	f.calls = append(f.calls, "arm "+rule)
+	return nil
+}
+
+func (f *fakeControl) Preview(ctx context.Context, action string) (*emergency.Preview, error) {
+	f.calls = append(f.calls, "preview "+action)
+	return &emergency.Preview{Action: action}, nil
+}
+
+func (f *fakeControl) Trigger(ctx context.Context, action, reason string) (*contracts.TxResult, error) {
+	f.calls = append(f.calls, "trigger "+action)
+	data, _ := os.ReadFile(f.auditFile)
+	f.attempts = strings.Count(string(data), `"phase":"attempt"`)
+	return &contracts.TxResult{Status: contracts.TxStatusReverted}, errors.New("execution reverted")
+}
+
+// TestServer_Admin 测试管理接口的认证、请求处理和审计日志
+func TestServer_Admin(t *testing.T) {
+	auditFile := filepath.Join(t.TempDir(), "audit", "admin.log")
+	audit, err := NewAuditLog(auditFile, zap.NewNop())
+	require.NoError(t, err)
+	defer audit.Close()
+
+	ctl := &fakeControl{auditFile: auditFile}
+	s := New(&config.ServerConfig{ListenAddr: "127.0.0.1:0"}, zap.NewNop())
+	s.HandleAdmin(ctl, testToken, audit)
+	require.NoError(t, s.Start())
+	defer s.Shutdown(context.Background())
+	base := "http://" + s.Addr()
+
+	post := func(path, token, body string) (int, map[string]any) {
+		req, err := http.NewRequest(http.MethodPost, base+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var out map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		return resp.StatusCode, out
+	}
+
+	// 认证失败不执行操作
+	code, _ := post("/admin/emergency/rearm", "wrong-token-wrong", `{}`)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Empty(t, ctl.calls)
+
+	code, _ = post("/admin/emergency/rearm", testToken, ``)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = post("/admin/emergency/disarm", testToken, `{"rule": "paused", "duration": "2h"}`)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = post("/admin/emergency/disarm", testToken, `{"rule": "paused", "duration": "soon"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	code, _ = post("/admin/emergency/arm", testToken, `{"unknown": 1}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 手动触发需要原因，执行失败时返回交易结果
+	code, _ = post("/admin/emergency/trigger", testToken, `{"action": "withdraw_eth"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	code, body := post("/admin/emergency/trigger", testToken, `{"action": "withdraw_eth", "reason": "演练"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, "execution reverted", body["error"])
+	assert.Equal(t, contracts.TxStatusReverted, body["result"].(map[string]any)["status"])
+	assert.Equal(t, 2, ctl.attempts, "执行前已写入 attempt 审计记录")
+
+	assert.Equal(t, []string{"reset", "disarm paused 2h0m0s", "trigger withdraw_eth"}, ctl.calls)
+
+	// 每次调用都写入审计日志
+	data, err := os.ReadFile(auditFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 9)
+	var entry AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, http.StatusUnauthorized, entry.Status)
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &entry))
+	assert.Equal(t, "/admin/emergency/disarm", entry.Path)
+	assert.JSONEq(t, `{"rule": "paused", "duration": "2h"}`, string(entry.Request))
+	assert.Empty(t, entry.Phase)
+
+	// 手动触发先写入 attempt 记录，执行后再写入结果
+	require.NoError(t, json.Unmarshal([]byte(lines[7]), &entry))
+	assert.Equal(t, AuditPhaseAttempt, entry.Phase)
+	assert.Zero(t, entry.Status)
+	assert.JSONEq(t, `{"action": "withdraw_eth", "reason": "演练"}`, string(entry.Request))
+	entry = AuditEntry{}
+	require.NoError(t, json.Unmarshal([]byte(lines[8]), &entry))
+	assert.Empty(t, entry.Phase)
+	assert.Equal(t, http.StatusUnprocessableEntity, entry.Status)
+	assert.Equal(t, "execution reverted", entry.Error)
+	assert.NotContains(t, string(data), testToken)
+}
+
+// TestLoadAdminToken 测试读取管理接口 Token
+func TestLoadAdminToken(t *testing.T) {
+	t.Setenv("TEST_ADMIN_TOKEN", " "+testToken+"\n")
+	token, err := LoadAdminToken(&config.AdminConfig{TokenEnv: "TEST_ADMIN_TOKEN"})
+	require.NoError(t, err)
+	assert.Equal(t, testToken, token)
+
+	t.Setenv("TEST_ADMIN_TOKEN", "short")
+	_, err = LoadAdminToken(&config.AdminConfig{TokenEnv: "TEST_ADMIN_TOKEN"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AuditEntry 一次管理接口调用的审计记录
+type AuditEntry struct {
+	Time    time.Time       `json:"time"`
+	Remote  string          `json:"remote"`
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Request json.RawMessage `json:"request,omitempty"` // 请求参数
+	Phase   string          `json:"phase,omitempty"`   // AuditPhaseAttempt 表示执行前写入的记录，执行结果另有一条记录
+	Status  int             `json:"status"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// AuditPhaseAttempt 执行不可撤销的操作前写入的审计记录，执行中进程崩溃时也能留下操作记录
+const AuditPhaseAttempt = "attempt"
+
+// AuditLog 管理接口审计日志，每次调用（包括认证失败）追加一行 JSON 并同时写入服务日志
+type AuditLog struct {
+	file   *os.File
+	logger *zap.Logger
+	mu     sync.Mutex
+}
+
+// NewAuditLog 以追加方式打开审计日志文件，目录不存在时自动创建
+func NewAuditLog(path string, logger *zap.Logger) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建审计日志目录失败: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	return &AuditLog{file: file, logger: logger}, nil
+}
+
+// Record 写入一条审计记录，写入失败只记录错误日志
+func (a *AuditLog) Record(entry AuditEntry) {
+	a.logger.Info("管理接口调用",
+		zap.String("remote", entry.Remote),
+		zap.String("method", entry.Method),
+		zap.String("path", entry.Path),
+		zap.ByteString("request", entry.Request),
+		zap.String("phase", entry.Phase),
+		zap.Int("status", entry.Status),
+		zap.String("error", entry.Error),
+	)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		a.logger.Error("序列化审计记录失败", zap.Error(err))
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(append(data, '\n')); err != nil {
+		a.logger.Error("写入审计日志失败", zap.Error(err))
+	}
+}
+
+// Close 关闭审计日志文件
+func (a *AuditLog) Close() error {
+	return a.file.Close()
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/monitor"
+)
+
+// StatusSource 提供就绪检查和运行状态，由 monitor.Monitor 实现
+type StatusSource interface {
+	Ready() error
+	Status() monitor.Status
+}
+
+// HandleHealth 注册 /healthz、/readyz 和 /status 接口
+//   - /healthz: 进程存活即返回 200
+//   - /readyz: 各链最近的检查都在允许的时间内时返回 200，否则返回 503 和原因
+//   - /status: 以 JSON 返回各合约最近一次检查的结果、RPC节点状态和应急响应状态
+func (s *Server) HandleHealth(src StatusSource) {
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+
+	s.mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := src.Ready(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(err.Error() + "\n"))
+			return
+		}
+		_, _ = w.Write([]byte("ok\n"))
+	})
+
+	s.mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(src.Status()); err != nil {
+			s.logger.Warn("返回运行状态失败", zap.Error(err))
+		}
+	})
+}
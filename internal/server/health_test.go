@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/monitor"
+)
+
+// fakeSource 测试用运行状态
+type fakeSource struct {
+	err error
+}
+
+func (f *fakeSource) Ready() error { return f.err }
+
+func (f *fakeSource) Status() monitor.Status {
+	return monitor.Status{
+		Ready:    f.err == nil,
+		Accounts: []monitor.AccountStatus{{Chain: "ink", Contract: "chaos_push_oracle", Value: 0.03, Block: 100, LastError: "超时"}},
+	}
+}
+
+// get 请求接口，返回状态码和响应内容
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+// TestServer_Health 测试存活、就绪和状态接口
+func TestServer_Health(t *testing.T) {
+	src := &fakeSource{}
+	s := New(&config.ServerConfig{ListenAddr: "127.0.0.1:0"}, zap.NewNop())
+	s.HandleHealth(src)
+	require.NoError(t, s.Start())
+	defer s.Shutdown(context.Background())
+	base := "http://" + s.Addr()
+
+	code, _ := get(t, base+"/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = get(t, base+"/readyz")
+	assert.Equal(t, http.StatusOK, code)
+
+	// 未就绪时返回 503 和原因，存活检查不受影响
+	src.err = errors.New("ink 尚未有合约检查成功")
+	code, body := get(t, base+"/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "ink 尚未有合约检查成功")
+	code, _ = get(t, base+"/healthz")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, body = get(t, base+"/status")
+	assert.Equal(t, http.StatusOK, code)
+	var status monitor.Status
+	require.NoError(t, json.Unmarshal([]byte(body), &status))
+	assert.False(t, status.Ready)
+	require.Len(t, status.Accounts, 1)
+	assert.Equal(t, uint64(100), status.Accounts[0].Block)
+	assert.Equal(t, "超时", status.Accounts[0].LastError)
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+)
+
+// 请求读取超时，防止慢速客户端占用连接
+const readHeaderTimeout = 10 * time.Second
+
+// Server 内置 HTTP 服务，提供 /metrics 抓取等接口
+type Server struct {
+	cfg      *config.ServerConfig
+	mux      *http.ServeMux
+	server   *http.Server
+	listener net.Listener
+	logger   *zap.Logger
+}
+
+// New 创建 HTTP 服务，调用 Start 后开始监听
+func New(cfg *config.ServerConfig, logger *zap.Logger) *Server {
+	mux := http.NewServeMux()
+	return &Server{
+		cfg:    cfg,
+		mux:    mux,
+		server: &http.Server{Handler: mux, ReadHeaderTimeout: readHeaderTimeout},
+		logger: logger,
+	}
+}
+
+// Handle 注册接口，需在 Start 之前调用
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start 监听配置的地址并在后台处理请求，监听失败时返回错误
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.cfg.GetListenAddr())
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败: %w", s.cfg.GetListenAddr(), err)
+	}
+	s.listener = listener
+	s.logger.Info("启动HTTP服务", zap.String("addr", listener.Addr().String()))
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("HTTP服务异常退出", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Addr 返回实际监听的地址，未启动时返回空字符串
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown 停止接收新请求，等待处理中的请求完成或 ctx 超时
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.listener == nil {
+		return nil
+	}
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("关闭HTTP服务失败: %w", err)
+	}
+	s.logger.Info("HTTP服务已关闭")
+	return nil
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"cs-projects-ink-eth-monitor/internal/config"
+	"cs-projects-ink-eth-monitor/internal/metrics"
+)
+
+// TestServer_Metrics 测试 /metrics 提供监控指标和运行时指标
+func TestServer_Metrics(t *testing.T) {
+	m := metrics.NewMetrics(&config.PrometheusConfig{Mode: config.PrometheusModeScrape}, zap.NewNop())
+	m.RegisterContractMetric("ink", "chaos_push_oracle")
+	m.SetContractMetric("ink", "chaos_push_oracle", 0.03)
+	require.NoError(t, m.Push(), "仅抓取时不推送")
+
+	s := New(&config.ServerConfig{ListenAddr: "127.0.0.1:0"}, zap.NewNop())
+	s.Handle("/metrics", m.Handler())
+	require.NoError(t, s.Start())
+	defer s.Shutdown(context.Background())
+
+	resp, err := http.Get("http://" + s.Addr() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `ink_eth_monitor_oracle_price_spread{chain="ink",contract="chaos_push_oracle"} 0.03`)
+	assert.Contains(t, string(body), "go_goroutines")
+	assert.Contains(t, string(body), "process_cpu_seconds_total")
+}
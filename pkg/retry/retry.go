@@ -11,8 +11,16 @@ import (
 // Func 重试函数类型
 type Func func() error
 
+// Notify 每次重试前调用，attempt 从 1 开始，err 为上一次执行的错误
+type Notify func(attempt int, err error)
+
 // Do 执行带重试的函数
 func Do(ctx context.Context, fn Func, maxRetries int, delay time.Duration, logger *zap.Logger) error {
+	return DoNotify(ctx, fn, maxRetries, delay, logger, nil)
+}
+
+// DoNotify 执行带重试的函数，每次重试前调用 notify（可以为 nil）
+func DoNotify(ctx context.Context, fn Func, maxRetries int, delay time.Duration, logger *zap.Logger, notify Notify) error {
 	var err error
 	for i := 0; i <= maxRetries; i++ {
 		// 检查上下文是否已取消
@@ -40,6 +48,9 @@ func Do(ctx context.Context, fn Func, maxRetries int, delay time.Duration, logge
 			zap.Duration("delay", delay),
 			zap.Error(err),
 		)
+		if notify != nil {
+			notify(i+1, err)
+		}
 
 		// 等待后重试
 		select {
@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestDoNotify 测试每次重试前调用 notify，最后一次失败后不再调用
+func TestDoNotify(t *testing.T) {
+	down := errors.New("down")
+	tests := []struct {
+		name       string
+		fails      int
+		maxRetries int
+		wantCalls  int
+		wantNotify []int
+		wantErr    bool
+	}{
+		{name: "首次成功", fails: 0, maxRetries: 3, wantCalls: 1},
+		{name: "重试后成功", fails: 2, maxRetries: 3, wantCalls: 3, wantNotify: []int{1, 2}},
+		{name: "重试耗尽", fails: 10, maxRetries: 3, wantCalls: 4, wantNotify: []int{1, 2, 3}, wantErr: true},
+		{name: "不重试", fails: 10, maxRetries: 0, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var notified []int
+			err := DoNotify(context.Background(), func() error {
+				calls++
+				if calls <= tt.fails {
+					return down
+				}
+				return nil
+			}, tt.maxRetries, 0, zap.NewNop(), func(attempt int, err error) {
+				assert.ErrorIs(t, err, down)
+				notified = append(notified, attempt)
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantNotify, notified)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, down)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	// 上下文取消时不再重试
+	ctx, cancel := context.WithCancel(context.Background())
+	notified := 0
+	err := DoNotify(ctx, func() error {
+		cancel()
+		return down
+	}, 3, 0, zap.NewNop(), func(int, error) { notified++ })
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, notified)
+}